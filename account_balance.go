@@ -1,18 +1,65 @@
 package emailverifygo
 
 import (
+	"context"
 	"net/url"
+
+	"gopkg.in/guregu/null.v4"
+)
+
+// Account plan types reported by AccountBalanceResponse.PlanType
+const (
+	PLAN_REGULAR = "regular" // Credit balance, with referral credits
+	PLAN_APPSUMO = "appsumo" // Daily credit allowance, with bonus credits
+	PLAN_UNKNOWN = "unknown"
 )
 
 // AccountBalanceResponse represents the response from the account balance API
-// Different fields may be present depending on the account type.
+// Different fields may be present depending on the account type. Optional
+// fields are invalid (null) when the API did not send them.
 type AccountBalanceResponse struct {
-	APIStatus            string `json:"api_status"`
-	DailyCreditsLimit    int    `json:"daily_credits_limit"`
-	ReferralCredits      int    `json:"referral_credits,omitempty"` // absent for appsumo users
-	RemainingCredits     int    `json:"remaining_credits,omitempty"` // absent for appsumo users
-	RemainingDailyCredits int   `json:"remaining_daily_credits,omitempty"`
-	BonusCredits         int    `json:"bonus_credits,omitempty"`
+	APIStatus             string   `json:"api_status"`
+	DailyCreditsLimit     null.Int `json:"daily_credits_limit"`
+	ReferralCredits       null.Int `json:"referral_credits"`        // absent for appsumo users
+	RemainingCredits      null.Int `json:"remaining_credits"`       // absent for appsumo users
+	RemainingDailyCredits null.Int `json:"remaining_daily_credits"` // absent for regular users
+	BonusCredits          null.Int `json:"bonus_credits"`           // absent for regular users
+}
+
+// PlanType detects the account plan from the fields present in the response.
+// AppSumo accounts report remaining daily credits, regular accounts remaining credits.
+func (r *AccountBalanceResponse) PlanType() string {
+	switch {
+	case r.RemainingDailyCredits.Valid:
+		return PLAN_APPSUMO
+	case r.RemainingCredits.Valid || r.ReferralCredits.Valid:
+		return PLAN_REGULAR
+	default:
+		return PLAN_UNKNOWN
+	}
+}
+
+// CreditsAvailable returns the credits the account can spend right now. AppSumo
+// accounts can spend what is left of today's allowance plus their bonus credits;
+// regular accounts their remaining credits plus any referral credits. The
+// daily limit of regular accounts is not applied here, since the response does
+// not say how much of it was used today; BudgetController tracks that locally.
+func (r *AccountBalanceResponse) CreditsAvailable() int {
+	var available int64
+	switch r.PlanType() {
+	case PLAN_APPSUMO:
+		daily := r.RemainingDailyCredits.Int64
+		if r.DailyCreditsLimit.Valid && daily > r.DailyCreditsLimit.Int64 {
+			daily = r.DailyCreditsLimit.Int64
+		}
+		available = daily + r.BonusCredits.ValueOrZero()
+	default:
+		available = r.RemainingCredits.ValueOrZero() + r.ReferralCredits.ValueOrZero()
+	}
+	if available < 0 {
+		return 0
+	}
+	return int(available)
 }
 
 // GetAccountBalance gets the current account balance and credits information
@@ -25,8 +72,17 @@ type AccountBalanceResponse struct {
 
 
 func GetAccountBalance() (*AccountBalanceResponse, error) {
-	var error_ error
-	response := &AccountBalanceResponse{}
+	return GetAccountBalanceContext(context.Background())
+}
+
+// GetAccountBalanceContext is like GetAccountBalance but carries ctx to the HTTP request and tracing spans
+func GetAccountBalanceContext(ctx context.Context) (response *AccountBalanceResponse, error_ error) {
+	ctx, span := startSpan(ctx, SPAN_ACCOUNT_BALANCE, Attr("endpoint", ENDPOINT_ACCOUNT_BALANCE))
+	defer func() {
+		finishSpan(span, error_)
+	}()
+
+	response = &AccountBalanceResponse{}
 
 	// Prepare URL with API key
 	url_to_request, error_ := PrepareURL(ENDPOINT_ACCOUNT_BALANCE, url.Values{})
@@ -35,6 +91,9 @@ func GetAccountBalance() (*AccountBalanceResponse, error) {
 	}
 	
 	// Make the request
-	error_ = DoGetRequest(url_to_request, response)
+	error_ = doGetRequest(ctx, url_to_request, response)
+	if error_ == nil {
+		span.SetAttributes(Attr("api_status", response.APIStatus))
+	}
 	return response, error_
 }
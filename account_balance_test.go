@@ -6,6 +6,7 @@ import (
 
 	"github.com/jarcoal/httpmock"
 	"github.com/stretchr/testify/assert"
+	"gopkg.in/guregu/null.v4"
 )
 
 func TestGetAccountBalance(t *testing.T) {
@@ -24,6 +25,9 @@ func TestGetAccountBalance(t *testing.T) {
 				return httpmock.NewStringResponse(400, `{"error": "Missing parameter: key."}`), nil
 			}
 			
+			if args.Get("key") == "appsumo_api_key" {
+				return httpmock.NewStringResponse(200, MOCK_ACCOUNT_BALANCE_APPSUMO_RESPONSE), nil
+			}
 			return httpmock.NewStringResponse(200, MOCK_ACCOUNT_BALANCE_RESPONSE), nil
 		},
 	)
@@ -33,8 +37,38 @@ func TestGetAccountBalance(t *testing.T) {
 		
 		assert.Nil(t, err, "Expected no error")
 		assert.Equal(t, "enabled", result.APIStatus, "Expected API status to be 'enabled'")
-		assert.Equal(t, 150, result.DailyCreditsLimit, "Expected daily credits limit to be 150")
-		assert.Equal(t, 15000, result.RemainingCredits, "Expected remaining credits to be 15000")
+		assert.Equal(t, null.IntFrom(150), result.DailyCreditsLimit, "Expected daily credits limit to be 150")
+		assert.Equal(t, null.IntFrom(15000), result.RemainingCredits, "Expected remaining credits to be 15000")
+		assert.False(t, result.RemainingDailyCredits.Valid, "Expected remaining daily credits to be absent")
+		assert.Equal(t, PLAN_REGULAR, result.PlanType(), "Expected a regular plan")
+		assert.Equal(t, 15000, result.CreditsAvailable(), "Expected the remaining credits to be available")
+	})
+
+	t.Run("TestGetAccountBalanceAppSumo", func(t *testing.T) {
+		SetApiKey("appsumo_api_key")
+		defer SetApiKey("test_api_key")
+
+		result, err := GetAccountBalance()
+
+		assert.Nil(t, err, "Expected no error")
+		assert.False(t, result.RemainingCredits.Valid, "Expected remaining credits to be absent")
+		assert.False(t, result.ReferralCredits.Valid, "Expected referral credits to be absent")
+		assert.Equal(t, null.IntFrom(120), result.RemainingDailyCredits, "Expected remaining daily credits to be 120")
+		assert.Equal(t, PLAN_APPSUMO, result.PlanType(), "Expected an AppSumo plan")
+		assert.Equal(t, 150, result.CreditsAvailable(), "Expected today's credits plus bonus credits to be available")
+	})
+
+	t.Run("TestCreditsAvailable", func(t *testing.T) {
+		regular := &AccountBalanceResponse{RemainingCredits: null.IntFrom(100), ReferralCredits: null.IntFrom(25)}
+		assert.Equal(t, 125, regular.CreditsAvailable(), "Expected referral credits to be added")
+
+		exhausted := &AccountBalanceResponse{DailyCreditsLimit: null.IntFrom(150), RemainingDailyCredits: null.IntFrom(0)}
+		assert.Equal(t, PLAN_APPSUMO, exhausted.PlanType(), "Expected a zero daily balance to still be an AppSumo plan")
+		assert.Equal(t, 0, exhausted.CreditsAvailable(), "Expected nothing available once today's credits are used")
+
+		unknown := &AccountBalanceResponse{APIStatus: "enabled"}
+		assert.Equal(t, PLAN_UNKNOWN, unknown.PlanType())
+		assert.Equal(t, 0, unknown.CreditsAvailable())
 	})
 	
 	t.Run("TestGetAccountBalanceWithInvalidAPIKey", func(t *testing.T) {
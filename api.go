@@ -1,6 +1,7 @@
 package emailverifygo
 
 import (
+	"context"
 	"fmt"
 	"net/url"
 )
@@ -11,6 +12,9 @@ type ValidateResponse struct {
 	Email     string `json:"email"`     // The email address being validated
 	Status    string `json:"status"`    // Status of the email (valid, invalid, etc.)
 	SubStatus string `json:"sub_status"` // Detailed status information
+
+	// Provider is the mail provider of the address's domain, set when SetProviderDetection is on
+	Provider *ProviderClassification `json:"provider,omitempty"`
 }
 
 // IsValid returns true if the email status is "valid"
@@ -29,10 +33,75 @@ func (v *ValidateResponse) IsValid() bool {
 //
 // API Reference: GET /api/v1/validate
 func Validate(email string) (*ValidateResponse, error) {
+	return ValidateContext(context.Background(), email)
+}
+
+// ValidateContext is like Validate but carries ctx to the HTTP request and tracing spans.
+// Concurrent calls for the same address share one request unless coalescing is disabled.
+// With SetDomainIntelligence, addresses on known catch-all or dead domains may be answered without a request,
+// and with SetDNSPreCheck, addresses on domains that cannot receive mail. SetProviderDetection attaches
+// the domain's mail provider to every result, including those answered without a request.
+func ValidateContext(ctx context.Context, email string) (*ValidateResponse, error) {
+	response, _, err := validateContext(ctx, email)
+	return response, err
+}
+
+// validateContext is ValidateContext, also reporting the credits this call
+// spent: none for answers given without a request or shared from another
+// caller's request
+func validateContext(ctx context.Context, email string) (response *ValidateResponse, credits int, err error) {
+	ctx, span := startSpan(ctx, SPAN_VALIDATE, Attr("endpoint", ENDPOINT_VALIDATE))
+	defer func() {
+		// Every answer gets its provider, however it was reached
+		if err == nil && response != nil {
+			attachProvider(ctx, response)
+			if response.Provider != nil {
+				span.SetAttributes(Attr("provider", response.Provider.Provider))
+			}
+		}
+		finishSpan(span, err)
+	}()
+
 	if email == "" {
-		return nil, fmt.Errorf("email cannot be empty")
+		return nil, 0, fmt.Errorf("email cannot be empty")
 	}
-	
+
+	if domainIntelligence != nil {
+		if known, ok := domainIntelligence.answer(email); ok {
+			span.SetAttributes(Attr("domain_intelligence", true), Attr("status", known.Status), Attr("sub_status", known.SubStatus))
+			return known, 0, nil
+		}
+	}
+
+	if dnsPreCheck != nil {
+		if dead, ok := precheckDNS(ctx, email); ok {
+			observeDomain(email, dead.Status, dead.SubStatus)
+			span.SetAttributes(Attr("dns_precheck", true), Attr("status", dead.Status), Attr("sub_status", dead.SubStatus))
+			return dead, 0, nil
+		}
+	}
+
+	if coalesceValidations {
+		var shared bool
+		response, shared, err = coalesceValidate(ctx, email)
+		span.SetAttributes(Attr("coalesced", shared))
+		if !shared {
+			credits = CREDITS_PER_VALIDATION
+		}
+	} else {
+		response, err = validateEmail(ctx, email)
+		credits = CREDITS_PER_VALIDATION
+	}
+	if err != nil {
+		return response, 0, err
+	}
+	observeDomain(email, response.Status, response.SubStatus)
+	span.SetAttributes(Attr("status", response.Status), Attr("sub_status", response.SubStatus))
+	return response, credits, nil
+}
+
+// validateEmail makes the validation request
+func validateEmail(ctx context.Context, email string) (*ValidateResponse, error) {
 	// Prepare the parameters
 	params := url.Values{}
 	params.Set("email", email)
@@ -45,6 +114,9 @@ func Validate(email string) (*ValidateResponse, error) {
 		return response, fmt.Errorf("failed to prepare URL: %w", err)
 	}
 	
-	err = DoGetRequest(url_to_request, response)
+	err = doGetRequest(ctx, url_to_request, response)
+	if err == nil {
+		observeCredits(ENDPOINT_VALIDATE, CREDITS_PER_VALIDATION)
+	}
 	return response, err
 }
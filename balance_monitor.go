@@ -0,0 +1,285 @@
+package emailverifygo
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Default settings used by NewBalanceMonitor when an option is left at zero
+const (
+	DEFAULT_BALANCE_MONITOR_INTERVAL = 15 * time.Minute
+	DEFAULT_BALANCE_FORECAST_WINDOW  = 7 * 24 * time.Hour
+)
+
+// BalanceSnapshot is the account balance at one point in time
+type BalanceSnapshot struct {
+	Time                  time.Time `json:"time"`
+	RemainingCredits      int       `json:"remaining_credits"`
+	RemainingDailyCredits int       `json:"remaining_daily_credits"`
+	BonusCredits          int       `json:"bonus_credits"`
+	Available             int       `json:"available"` // Credits that could be spent right now
+}
+
+// BalanceHistory persists balance snapshots as a time series
+type BalanceHistory interface {
+	Append(snapshot BalanceSnapshot) error
+	Snapshots() ([]BalanceSnapshot, error)
+}
+
+// MemoryBalanceHistory keeps snapshots in memory
+type MemoryBalanceHistory struct {
+	mu        sync.Mutex
+	snapshots []BalanceSnapshot
+}
+
+// NewMemoryBalanceHistory creates an empty in-memory history
+func NewMemoryBalanceHistory() *MemoryBalanceHistory {
+	return &MemoryBalanceHistory{}
+}
+
+// Append adds a snapshot to the history
+func (h *MemoryBalanceHistory) Append(snapshot BalanceSnapshot) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.snapshots = append(h.snapshots, snapshot)
+	return nil
+}
+
+// Snapshots returns every snapshot in time order
+func (h *MemoryBalanceHistory) Snapshots() ([]BalanceSnapshot, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]BalanceSnapshot(nil), h.snapshots...), nil
+}
+
+// FileBalanceHistory appends snapshots to a file, one JSON object per line
+type FileBalanceHistory struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewFileBalanceHistory creates a history backed by the file at path
+func NewFileBalanceHistory(path string) *FileBalanceHistory {
+	return &FileBalanceHistory{path: path}
+}
+
+// Append adds a snapshot to the end of the file
+func (h *FileBalanceHistory) Append(snapshot BalanceSnapshot) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	line, err := json.Marshal(snapshot)
+	if err != nil {
+		return fmt.Errorf("failed to encode balance snapshot: %w", err)
+	}
+
+	file, err := os.OpenFile(h.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open balance history: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write balance history: %w", err)
+	}
+	return nil
+}
+
+// Snapshots reads every snapshot from the file in time order
+func (h *FileBalanceHistory) Snapshots() ([]BalanceSnapshot, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	file, err := os.Open(h.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open balance history: %w", err)
+	}
+	defer file.Close()
+
+	var snapshots []BalanceSnapshot
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var snapshot BalanceSnapshot
+		if err := json.Unmarshal(scanner.Bytes(), &snapshot); err != nil {
+			return nil, fmt.Errorf("failed to decode balance history: %w", err)
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read balance history: %w", err)
+	}
+
+	sort.SliceStable(snapshots, func(i, j int) bool { return snapshots[i].Time.Before(snapshots[j].Time) })
+	return snapshots, nil
+}
+
+// BalanceForecast describes how fast credits are being spent
+type BalanceForecast struct {
+	BurnRatePerHour     float64   // Average credits spent per hour, top-ups excluded
+	ProjectedExhaustion time.Time // When the available credits run out at that rate, zero if they don't
+}
+
+// ForecastBalance computes the burn rate over the snapshots taken within window
+// of the latest one, and projects when the available credits run out. Increases
+// between snapshots, such as top-ups or daily resets, are not counted as spending.
+func ForecastBalance(snapshots []BalanceSnapshot, window time.Duration) BalanceForecast {
+	var forecast BalanceForecast
+	if len(snapshots) < 2 {
+		return forecast
+	}
+
+	latest := snapshots[len(snapshots)-1]
+	start := 0
+	for start < len(snapshots)-1 && latest.Time.Sub(snapshots[start].Time) > window {
+		start++
+	}
+
+	spent := 0
+	for i := start + 1; i < len(snapshots); i++ {
+		if drop := snapshots[i-1].Available - snapshots[i].Available; drop > 0 {
+			spent += drop
+		}
+	}
+	elapsed := latest.Time.Sub(snapshots[start].Time)
+	if elapsed <= 0 || spent == 0 {
+		return forecast
+	}
+
+	forecast.BurnRatePerHour = float64(spent) / elapsed.Hours()
+	hoursLeft := float64(latest.Available) / forecast.BurnRatePerHour
+	forecast.ProjectedExhaustion = latest.Time.Add(time.Duration(hoursLeft * float64(time.Hour)))
+	return forecast
+}
+
+// BalanceAlert is fired when the available credits fall to or below a threshold
+type BalanceAlert struct {
+	Threshold int
+	Snapshot  BalanceSnapshot
+	Forecast  BalanceForecast
+}
+
+// BalanceMonitorOptions configures a BalanceMonitor
+type BalanceMonitorOptions struct {
+	// Interval is how often the balance is fetched
+	Interval time.Duration
+	// History stores the snapshots. Defaults to an in-memory history.
+	History BalanceHistory
+	// Window is how far back the burn rate is computed
+	Window time.Duration
+	// Thresholds are available-credit levels that fire an alert when crossed downwards
+	Thresholds []int
+	// OnAlert is called once per threshold crossing. It fires again only after
+	// the credits have risen back above the threshold.
+	OnAlert func(BalanceAlert)
+	// OnError is called when fetching or storing the balance fails
+	OnError func(error)
+}
+
+// BalanceMonitor periodically records the account balance, forecasts when
+// credits run out and alerts when thresholds are crossed
+type BalanceMonitor struct {
+	opts BalanceMonitorOptions
+
+	mu    sync.Mutex
+	fired map[int]bool
+}
+
+// NewBalanceMonitor creates a monitor. Call Run to start it.
+func NewBalanceMonitor(opts BalanceMonitorOptions) *BalanceMonitor {
+	if opts.Interval <= 0 {
+		opts.Interval = DEFAULT_BALANCE_MONITOR_INTERVAL
+	}
+	if opts.Window <= 0 {
+		opts.Window = DEFAULT_BALANCE_FORECAST_WINDOW
+	}
+	if opts.History == nil {
+		opts.History = NewMemoryBalanceHistory()
+	}
+	return &BalanceMonitor{opts: opts, fired: make(map[int]bool)}
+}
+
+// Run checks the balance every Interval until ctx is done
+func (m *BalanceMonitor) Run(ctx context.Context) error {
+	ticker := time.NewTicker(m.opts.Interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := m.Check(ctx); err != nil && m.opts.OnError != nil {
+			m.opts.OnError(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check fetches the balance once, records it and fires any due alerts
+func (m *BalanceMonitor) Check(ctx context.Context) (BalanceSnapshot, error) {
+	balance, err := GetAccountBalanceContext(ctx)
+	if err != nil {
+		return BalanceSnapshot{}, err
+	}
+
+	snapshot := BalanceSnapshot{
+		Time:                  time.Now(),
+		RemainingCredits:      int(balance.RemainingCredits.ValueOrZero()),
+		RemainingDailyCredits: int(balance.RemainingDailyCredits.ValueOrZero()),
+		BonusCredits:          int(balance.BonusCredits.ValueOrZero()),
+		Available:             balance.CreditsAvailable(),
+	}
+	if err := m.opts.History.Append(snapshot); err != nil {
+		return snapshot, err
+	}
+
+	for _, threshold := range m.dueAlerts(snapshot.Available) {
+		if m.opts.OnAlert == nil {
+			continue
+		}
+		forecast, _ := m.Forecast()
+		m.opts.OnAlert(BalanceAlert{Threshold: threshold, Snapshot: snapshot, Forecast: forecast})
+	}
+	return snapshot, nil
+}
+
+// Forecast computes the burn rate and projected exhaustion from the recorded history
+func (m *BalanceMonitor) Forecast() (BalanceForecast, error) {
+	snapshots, err := m.opts.History.Snapshots()
+	if err != nil {
+		return BalanceForecast{}, err
+	}
+	return ForecastBalance(snapshots, m.opts.Window), nil
+}
+
+// dueAlerts returns the thresholds newly crossed at this level, and re-arms those risen above
+func (m *BalanceMonitor) dueAlerts(available int) []int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var due []int
+	for _, threshold := range m.opts.Thresholds {
+		switch {
+		case available <= threshold && !m.fired[threshold]:
+			m.fired[threshold] = true
+			due = append(due, threshold)
+		case available > threshold:
+			m.fired[threshold] = false
+		}
+	}
+	return due
+}
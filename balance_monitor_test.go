@@ -0,0 +1,118 @@
+package emailverifygo
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBalanceMonitor(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	SetApiKey("test_api_key")
+
+	var remaining atomic.Int64
+	httpmock.RegisterResponder("GET", `=~^(.*)`+ENDPOINT_ACCOUNT_BALANCE+`(.*)\z`,
+		func(r *http.Request) (*http.Response, error) {
+			if remaining.Load() < 0 {
+				return httpmock.NewStringResponse(500, MOCK_ERROR_RESPONSE), nil
+			}
+			body := fmt.Sprintf(`{"api_status": "enabled", "remaining_credits": %d, "bonus_credits": 5}`, remaining.Load())
+			return httpmock.NewStringResponse(200, body), nil
+		},
+	)
+
+	ctx := context.Background()
+
+	t.Run("TestThresholdAlerts", func(t *testing.T) {
+		var alerts []BalanceAlert
+		monitor := NewBalanceMonitor(BalanceMonitorOptions{
+			Thresholds: []int{1000, 100},
+			OnAlert:    func(alert BalanceAlert) { alerts = append(alerts, alert) },
+		})
+
+		for _, credits := range []int64{2000, 900, 800, 50, 40, 5000, 500, 60} {
+			remaining.Store(credits)
+			_, err := monitor.Check(ctx)
+			assert.Nil(t, err, "Expected no error checking the balance")
+		}
+
+		if assert.Len(t, alerts, 4, "Expected one alert per downward crossing") {
+			assert.Equal(t, 1000, alerts[0].Threshold)
+			assert.Equal(t, 900, alerts[0].Snapshot.Available)
+			assert.Equal(t, 5, alerts[0].Snapshot.BonusCredits)
+			assert.Equal(t, 100, alerts[1].Threshold)
+			assert.Equal(t, 50, alerts[1].Snapshot.Available)
+			assert.Equal(t, 1000, alerts[2].Threshold, "Expected the threshold to re-arm after a top-up")
+			assert.Equal(t, 500, alerts[2].Snapshot.Available)
+			assert.Equal(t, 100, alerts[3].Threshold, "Expected the lower threshold to re-arm too")
+		}
+	})
+
+	t.Run("TestForecastBalance", func(t *testing.T) {
+		start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		snapshots := []BalanceSnapshot{
+			{Time: start, Available: 1000},
+			{Time: start.Add(time.Hour), Available: 900},
+			{Time: start.Add(2 * time.Hour), Available: 1900}, // Top-up
+			{Time: start.Add(3 * time.Hour), Available: 1800},
+		}
+
+		forecast := ForecastBalance(snapshots, 24*time.Hour)
+		assert.InDelta(t, 200.0/3, forecast.BurnRatePerHour, 0.001, "Expected top-ups to be left out of the burn rate")
+		assert.Equal(t, start.Add(30*time.Hour), forecast.ProjectedExhaustion.Round(time.Minute))
+
+		forecast = ForecastBalance(snapshots, 90*time.Minute)
+		assert.InDelta(t, 100.0, forecast.BurnRatePerHour, 0.001, "Expected only snapshots within the window to count")
+
+		forecast = ForecastBalance(snapshots[:1], 24*time.Hour)
+		assert.Zero(t, forecast.BurnRatePerHour, "Expected no burn rate from a single snapshot")
+		assert.True(t, forecast.ProjectedExhaustion.IsZero(), "Expected no exhaustion date without spending")
+	})
+
+	t.Run("TestFileBalanceHistory", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "balance.jsonl")
+		monitor := NewBalanceMonitor(BalanceMonitorOptions{History: NewFileBalanceHistory(path)})
+
+		remaining.Store(300)
+		_, err := monitor.Check(ctx)
+		assert.Nil(t, err)
+		remaining.Store(200)
+		_, err = monitor.Check(ctx)
+		assert.Nil(t, err)
+
+		snapshots, err := NewFileBalanceHistory(path).Snapshots()
+		assert.Nil(t, err, "Expected the history to be readable by a new instance")
+		if assert.Len(t, snapshots, 2) {
+			assert.Equal(t, 300, snapshots[0].RemainingCredits)
+			assert.Equal(t, 200, snapshots[1].Available)
+		}
+
+		forecast, err := monitor.Forecast()
+		assert.Nil(t, err)
+		assert.True(t, forecast.BurnRatePerHour > 0, "Expected a burn rate from the persisted history")
+	})
+
+	t.Run("TestRunReportsErrors", func(t *testing.T) {
+		remaining.Store(-1)
+		var errorCount atomic.Int32
+		monitor := NewBalanceMonitor(BalanceMonitorOptions{
+			Interval: 10 * time.Millisecond,
+			OnError:  func(err error) { errorCount.Add(1) },
+		})
+
+		runCtx, cancel := context.WithTimeout(ctx, 35*time.Millisecond)
+		defer cancel()
+		err := monitor.Run(runCtx)
+		assert.ErrorIs(t, err, context.DeadlineExceeded, "Expected Run to stop when the context is done")
+		assert.True(t, errorCount.Load() >= 2, "Expected every failed check to be reported")
+	})
+}
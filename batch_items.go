@@ -0,0 +1,114 @@
+package emailverifygo
+
+import (
+	"errors"
+	"sync"
+)
+
+// ErrBatchItemsNotFound is returned when no items were registered for a task ID
+var ErrBatchItemsNotFound = errors.New("no batch items registered for this task")
+
+// BatchItem is one address to validate in a batch together with the caller's own
+// identifiers. The ExternalID and Metadata never leave the process; they are kept
+// locally and reattached to the result when it arrives.
+type BatchItem struct {
+	Address    string         `json:"address"`
+	ExternalID string         `json:"external_id,omitempty"`
+	Metadata   map[string]any `json:"metadata,omitempty"`
+}
+
+// BatchItemResult joins a submitted item with its validation result
+type BatchItemResult struct {
+	BatchItem
+	Result *EmailBatchResult `json:"result"` // nil until the API returns a result for the address
+}
+
+// batchItems keeps the items of tasks submitted with ValidateBatchItems, by task ID
+var batchItems = struct {
+	sync.Mutex
+	tasks map[int][]BatchItem
+}{tasks: make(map[int][]BatchItem)}
+
+// ValidateBatchItems submits a batch of items for verification and keeps the items
+// locally so GetBatchItemResults can reattach their IDs and metadata
+//
+// Parameters:
+//   - title: The name for this batch validation task
+//   - items: The items to validate
+//
+// Returns:
+//   - *BatchValidateResponse: The initial batch submission response
+//   - error: Any error that occurred during submission
+//
+// API Reference: POST /api/v1/validate-batch
+func ValidateBatchItems(title string, items []BatchItem) (*BatchValidateResponse, error) {
+	response, err := ValidateBatch(title, batchItemAddresses(items))
+	if err != nil {
+		return response, err
+	}
+
+	batchItems.Lock()
+	batchItems.tasks[response.TaskID] = append([]BatchItem(nil), items...)
+	batchItems.Unlock()
+	return response, nil
+}
+
+// GetBatchItemResults retrieves the results of a task submitted with ValidateBatchItems
+// and attaches each result to the items it belongs to
+//
+// Parameters:
+//   - taskID: The ID of the batch validation task to retrieve results for
+//
+// Returns:
+//   - *BatchResultResponse: The raw batch validation results
+//   - []BatchItemResult: The submitted items, in submission order, with their results
+//   - error: Any error that occurred during retrieval
+//
+// API Reference: GET /api/v1/get-result-bulk-verification-task
+func GetBatchItemResults(taskID int) (*BatchResultResponse, []BatchItemResult, error) {
+	batchItems.Lock()
+	items, ok := batchItems.tasks[taskID]
+	batchItems.Unlock()
+	if !ok {
+		return nil, nil, ErrBatchItemsNotFound
+	}
+
+	response, err := GetBatchResults(taskID)
+	if err != nil {
+		return response, nil, err
+	}
+	return response, AttachBatchItems(items, response), nil
+}
+
+// ForgetBatchItems drops the locally kept items of a task once its results are processed
+func ForgetBatchItems(taskID int) {
+	batchItems.Lock()
+	delete(batchItems.tasks, taskID)
+	batchItems.Unlock()
+}
+
+// AttachBatchItems joins items with batch results by normalized address, so case
+// differences don't break the match and duplicated addresses all get their result
+func AttachBatchItems(items []BatchItem, results *BatchResultResponse) []BatchItemResult {
+	byAddress := make(map[string]*EmailBatchResult)
+	if results != nil {
+		for i := range results.Results.EmailBatch {
+			result := &results.Results.EmailBatch[i]
+			byAddress[NormalizeEmail(result.Address)] = result
+		}
+	}
+
+	joined := make([]BatchItemResult, len(items))
+	for i, item := range items {
+		joined[i] = BatchItemResult{BatchItem: item, Result: byAddress[NormalizeEmail(item.Address)]}
+	}
+	return joined
+}
+
+func batchItemAddresses(items []BatchItem) []string {
+	addresses := make([]string, len(items))
+	for i, item := range items {
+		addresses[i] = item.Address
+	}
+	return addresses
+}
@@ -0,0 +1,63 @@
+package emailverifygo
+
+import (
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBatchItems(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	SetApiKey("test_api_key")
+
+	httpmock.RegisterResponder("POST", `=~^(.*)`+ENDPOINT_VALIDATE_BATCH+`(.*)\z`,
+		httpmock.NewStringResponder(200, MOCK_BATCH_RESPONSE))
+	httpmock.RegisterResponder("GET", `=~^(.*)`+ENDPOINT_BATCH_RESULT+`(.*)\z`,
+		httpmock.NewStringResponder(200, MOCK_BATCH_RESULTS_RESPONSE))
+
+	items := []BatchItem{
+		{Address: "Valid@Example.com", ExternalID: "crm-1", Metadata: map[string]any{"owner": "alice"}},
+		{Address: "valid@example.com", ExternalID: "crm-2"},
+		{Address: "invalid@example.com", ExternalID: "crm-3"},
+		{Address: "pending@example.com", ExternalID: "crm-4"},
+	}
+
+	t.Run("TestGetBatchItemResults", func(t *testing.T) {
+		response, err := ValidateBatchItems("Test Batch", items)
+		assert.Nil(t, err, "Expected no error submitting items")
+
+		_, joined, err := GetBatchItemResults(response.TaskID)
+		assert.Nil(t, err, "Expected no error retrieving item results")
+		assert.Equal(t, 4, len(joined), "Expected one entry per submitted item")
+
+		assert.Equal(t, "crm-1", joined[0].ExternalID, "Expected external ID to be kept")
+		assert.Equal(t, "alice", joined[0].Metadata["owner"], "Expected metadata to be kept")
+		assert.Equal(t, STATUS_VALID, joined[0].Result.Status, "Expected a case-insensitive match")
+		assert.Equal(t, STATUS_VALID, joined[1].Result.Status, "Expected the duplicate to get the same result")
+		assert.Equal(t, STATUS_INVALID, joined[2].Result.Status, "Expected the invalid result")
+		assert.Nil(t, joined[3].Result, "Expected no result for an address the API did not return")
+
+		ForgetBatchItems(response.TaskID)
+		_, _, err = GetBatchItemResults(response.TaskID)
+		assert.ErrorIs(t, err, ErrBatchItemsNotFound, "Expected items to be forgotten")
+	})
+
+	t.Run("TestBatchItemsJobSurvivesStore", func(t *testing.T) {
+		store := NewMemoryBatchJobStore()
+		job, _, err := SubmitBatchItemsJob(store, "Test Batch", items)
+		assert.Nil(t, err, "Expected no error submitting items job")
+
+		stored, err := store.Get(job.TaskID)
+		assert.Nil(t, err, "Expected job to be stored")
+
+		results, err := GetBatchResults(stored.TaskID)
+		assert.Nil(t, err, "Expected no error retrieving results")
+
+		joined := stored.AttachResults(results)
+		assert.Equal(t, "crm-3", joined[2].ExternalID, "Expected stored items to keep their IDs")
+		assert.Equal(t, SUBSTATUS_MAILBOX_NOT_FOUND, joined[2].Result.SubStatus, "Expected stored items to get results")
+	})
+}
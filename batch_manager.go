@@ -0,0 +1,349 @@
+package emailverifygo
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Default settings used by NewBatchManager when an option is left at zero
+const (
+	DEFAULT_BATCH_POLL_INTERVAL     = 30 * time.Second
+	DEFAULT_BATCH_REQUEST_INTERVAL  = time.Second
+	DEFAULT_BATCH_MAX_POLL_FAILURES = 5
+)
+
+// BatchProgress is the latest known state of one tracked batch task
+type BatchProgress struct {
+	TaskID             int
+	Title              string
+	Status             string
+	CountChecked       int
+	CountTotal         int
+	ProgressPercentage float64
+	Done               bool  // The task is verified or polling gave up
+	Err                error // The last polling error, if any
+	UpdatedAt          time.Time
+}
+
+// AggregateProgress summarises every task tracked by a BatchManager
+type AggregateProgress struct {
+	Tasks              int
+	Completed          int
+	Failed             int
+	CountChecked       int
+	CountTotal         int
+	ProgressPercentage float64
+}
+
+// BatchEvent is emitted when a tracked task finishes, successfully or not
+type BatchEvent struct {
+	TaskID int
+	Result *BatchResultResponse // Final results, nil if polling failed
+	Err    error                // Why polling gave up, nil on success
+}
+
+// BatchManagerOptions configures a BatchManager
+type BatchManagerOptions struct {
+	// PollInterval is how often each pending task is polled
+	PollInterval time.Duration
+	// RequestInterval is the minimum spacing between any two API calls made by the manager
+	RequestInterval time.Duration
+	// MaxPollFailures is how many consecutive polling errors mark a task as failed
+	MaxPollFailures int
+	// Store, if set, records submitted tasks and their status transitions
+	Store BatchJobStore
+	// OnProgress is called after every successful poll of a task
+	OnProgress func(BatchProgress)
+	// OnComplete is called once per task when it finishes
+	OnComplete func(BatchEvent)
+	// Events, if set, receives one event per finished task. Run blocks on it, so it must be drained.
+	Events chan<- BatchEvent
+}
+
+type trackedBatch struct {
+	progress BatchProgress
+	failures int
+}
+
+// BatchManager tracks many batch tasks at once and polls them on a shared
+// schedule, so callers don't each have to hand-roll a polling loop.
+type BatchManager struct {
+	opts    BatchManagerOptions
+	limiter *rateLimiter
+
+	mu      sync.Mutex
+	tasks   map[int]*trackedBatch
+	changed chan struct{}
+}
+
+// NewBatchManager creates a manager. Call Run to start polling.
+func NewBatchManager(opts BatchManagerOptions) *BatchManager {
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = DEFAULT_BATCH_POLL_INTERVAL
+	}
+	if opts.RequestInterval <= 0 {
+		opts.RequestInterval = DEFAULT_BATCH_REQUEST_INTERVAL
+	}
+	if opts.MaxPollFailures <= 0 {
+		opts.MaxPollFailures = DEFAULT_BATCH_MAX_POLL_FAILURES
+	}
+	return &BatchManager{
+		opts:    opts,
+		limiter: newRateLimiter(opts.RequestInterval),
+		tasks:   make(map[int]*trackedBatch),
+		changed: make(chan struct{}),
+	}
+}
+
+// Submit validates a batch with ValidateBatch and starts tracking the returned task
+func (m *BatchManager) Submit(title string, emails []string) (*BatchValidateResponse, error) {
+	if err := m.limiter.Wait(context.Background()); err != nil {
+		return nil, err
+	}
+
+	var (
+		response *BatchValidateResponse
+		err      error
+	)
+	if m.opts.Store != nil {
+		_, response, err = SubmitBatchJob(m.opts.Store, title, emails)
+	} else {
+		response, err = ValidateBatch(title, emails)
+	}
+	if err != nil {
+		return response, err
+	}
+
+	m.Track(response.TaskID, title)
+	return response, nil
+}
+
+// Track starts tracking an already submitted task
+func (m *BatchManager) Track(taskID int, title string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.tasks[taskID]; ok {
+		return
+	}
+	m.tasks[taskID] = &trackedBatch{progress: BatchProgress{
+		TaskID:    taskID,
+		Title:     title,
+		Status:    BATCH_STATUS_SUBMITTED,
+		UpdatedAt: time.Now(),
+	}}
+	m.notify()
+}
+
+// TrackStored starts tracking every unfinished job found in the manager's store
+func (m *BatchManager) TrackStored() error {
+	if m.opts.Store == nil {
+		return nil
+	}
+
+	jobs, err := m.opts.Store.List()
+	if err != nil {
+		return err
+	}
+	for _, job := range jobs {
+		if !job.IsFinished() {
+			m.Track(job.TaskID, job.Title)
+		}
+	}
+	return nil
+}
+
+// Progress returns the latest known progress of a tracked task
+func (m *BatchManager) Progress(taskID int) (BatchProgress, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	task, ok := m.tasks[taskID]
+	if !ok {
+		return BatchProgress{}, false
+	}
+	return task.progress, true
+}
+
+// AllProgress returns the progress of every tracked task ordered by task ID
+func (m *BatchManager) AllProgress() []BatchProgress {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	list := make([]BatchProgress, 0, len(m.tasks))
+	for _, task := range m.tasks {
+		list = append(list, task.progress)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].TaskID < list[j].TaskID })
+	return list
+}
+
+// Aggregate returns the combined progress of every tracked task
+func (m *BatchManager) Aggregate() AggregateProgress {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var aggregate AggregateProgress
+	for _, task := range m.tasks {
+		aggregate.Tasks++
+		if task.progress.Done {
+			if task.progress.Err != nil {
+				aggregate.Failed++
+			} else {
+				aggregate.Completed++
+			}
+		}
+		aggregate.CountChecked += task.progress.CountChecked
+		aggregate.CountTotal += task.progress.CountTotal
+	}
+	if aggregate.CountTotal > 0 {
+		aggregate.ProgressPercentage = float64(aggregate.CountChecked) * 100 / float64(aggregate.CountTotal)
+	}
+	return aggregate
+}
+
+// Run polls every pending task each PollInterval until ctx is done
+func (m *BatchManager) Run(ctx context.Context) error {
+	ticker := time.NewTicker(m.opts.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := m.pollPending(ctx); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Wait blocks until every tracked task is done or ctx is done
+func (m *BatchManager) Wait(ctx context.Context) error {
+	for {
+		m.mu.Lock()
+		pending := len(m.pendingLocked())
+		changed := m.changed
+		m.mu.Unlock()
+
+		if pending == 0 {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-changed:
+		}
+	}
+}
+
+func (m *BatchManager) pollPending(ctx context.Context) error {
+	m.mu.Lock()
+	pending := m.pendingLocked()
+	m.mu.Unlock()
+
+	for _, taskID := range pending {
+		if err := m.limiter.Wait(ctx); err != nil {
+			return err
+		}
+
+		result, err := GetBatchResultsContext(ctx, taskID)
+		if err == nil {
+			observeBatchPoll(taskID, result.Status)
+		}
+		progress, event := m.record(taskID, result, err)
+		if err == nil && m.opts.OnProgress != nil {
+			m.opts.OnProgress(progress)
+		}
+		if event == nil {
+			continue
+		}
+
+		if m.opts.OnComplete != nil {
+			m.opts.OnComplete(*event)
+		}
+		if m.opts.Events != nil {
+			select {
+			case m.opts.Events <- *event:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	}
+	return nil
+}
+
+// record stores the outcome of one poll and returns the updated progress,
+// plus an event if the task finished
+func (m *BatchManager) record(taskID int, result *BatchResultResponse, err error) (BatchProgress, *BatchEvent) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	task := m.tasks[taskID]
+	progress := &task.progress
+	progress.UpdatedAt = time.Now()
+	progress.Err = err
+
+	if err != nil {
+		task.failures++
+		if task.failures < m.opts.MaxPollFailures {
+			return *progress, nil
+		}
+		progress.Done = true
+		m.notify()
+		return *progress, &BatchEvent{TaskID: taskID, Err: err}
+	}
+
+	task.failures = 0
+	if result.Status != "" && result.Status != progress.Status {
+		logBatchTransition(taskID, progress.Status, result.Status)
+		progress.Status = result.Status
+		m.saveStatus(taskID, result.Status)
+	}
+	progress.CountChecked = result.CountChecked
+	progress.CountTotal = result.CountTotal
+	progress.ProgressPercentage = result.ProgressPercentage
+
+	if !result.IsComplete() {
+		return *progress, nil
+	}
+	progress.Done = true
+	m.notify()
+	return *progress, &BatchEvent{TaskID: taskID, Result: result}
+}
+
+func (m *BatchManager) saveStatus(taskID int, status string) {
+	if m.opts.Store == nil {
+		return
+	}
+
+	job, err := m.opts.Store.Get(taskID)
+	if err != nil {
+		return
+	}
+	job.Status = status
+	job.UpdatedAt = time.Now()
+	m.opts.Store.Save(job)
+}
+
+func (m *BatchManager) pendingLocked() []int {
+	var pending []int
+	for id, task := range m.tasks {
+		if !task.progress.Done {
+			pending = append(pending, id)
+		}
+	}
+	sort.Ints(pending)
+	return pending
+}
+
+// notify wakes up anyone blocked in Wait. Callers must hold m.mu.
+func (m *BatchManager) notify() {
+	close(m.changed)
+	m.changed = make(chan struct{})
+}
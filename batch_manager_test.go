@@ -0,0 +1,86 @@
+package emailverifygo
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBatchManager(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	SetApiKey("test_api_key")
+
+	httpmock.RegisterResponder("POST", `=~^(.*)`+ENDPOINT_VALIDATE_BATCH+`(.*)\z`,
+		httpmock.NewStringResponder(200, MOCK_BATCH_RESPONSE))
+
+	var mu sync.Mutex
+	polls := map[string]int{}
+	httpmock.RegisterResponder("GET", `=~^(.*)`+ENDPOINT_BATCH_RESULT+`(.*)\z`,
+		func(r *http.Request) (*http.Response, error) {
+			taskID := r.URL.Query().Get("task_id")
+			mu.Lock()
+			polls[taskID]++
+			count := polls[taskID]
+			mu.Unlock()
+
+			switch {
+			case taskID == "999":
+				return httpmock.NewStringResponse(404, `{"error": "Task not found"}`), nil
+			case count < 2:
+				return httpmock.NewStringResponse(200, MOCK_BATCH_IN_PROGRESS_RESPONSE), nil
+			default:
+				return httpmock.NewStringResponse(200, MOCK_BATCH_RESULTS_RESPONSE), nil
+			}
+		},
+	)
+
+	events := make(chan BatchEvent, 4)
+	store := NewMemoryBatchJobStore()
+	manager := NewBatchManager(BatchManagerOptions{
+		PollInterval:    time.Millisecond,
+		RequestInterval: time.Microsecond,
+		MaxPollFailures: 2,
+		Store:           store,
+		Events:          events,
+	})
+
+	_, err := manager.Submit("Test Batch", []string{"valid@example.com"})
+	assert.Nil(t, err, "Expected no error submitting")
+	manager.Track(999, "Unknown task")
+
+	progress, ok := manager.Progress(12345)
+	assert.True(t, ok, "Expected submitted task to be tracked")
+	assert.Equal(t, BATCH_STATUS_SUBMITTED, progress.Status, "Expected submitted status before polling")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	go manager.Run(ctx)
+
+	assert.Nil(t, manager.Wait(ctx), "Expected all tasks to finish")
+
+	finished := map[int]BatchEvent{}
+	for i := 0; i < 2; i++ {
+		event := <-events
+		finished[event.TaskID] = event
+	}
+	assert.Nil(t, finished[12345].Err, "Expected task 12345 to succeed")
+	assert.Equal(t, 3, len(finished[12345].Result.Results.EmailBatch), "Expected final results in the event")
+	assert.NotNil(t, finished[999].Err, "Expected task 999 to fail after repeated errors")
+
+	aggregate := manager.Aggregate()
+	assert.Equal(t, 2, aggregate.Tasks, "Expected two tracked tasks")
+	assert.Equal(t, 1, aggregate.Completed, "Expected one completed task")
+	assert.Equal(t, 1, aggregate.Failed, "Expected one failed task")
+	assert.Equal(t, 3, aggregate.CountTotal, "Expected totals from the completed task")
+
+	job, err := store.Get(12345)
+	assert.Nil(t, err, "Expected submitted task in the store")
+	assert.True(t, job.IsFinished(), "Expected store to record the verified status")
+}
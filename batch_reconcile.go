@@ -0,0 +1,108 @@
+package emailverifygo
+
+// Reason attached to rejected addresses that the API did not explain itself
+const (
+	REJECT_REASON_SYNTAX = "failed local syntax check"
+)
+
+// DuplicateGroup lists the submitted inputs that collapsed into one address
+type DuplicateGroup struct {
+	Address string   `json:"address"` // The normalized address the API validated once
+	Inputs  []string `json:"inputs"`  // Every submitted form of that address
+}
+
+// BatchReconciliation compares what was submitted in a batch with what came back
+type BatchReconciliation struct {
+	Submitted  int                `json:"submitted"`  // Number of addresses submitted
+	Returned   int                `json:"returned"`   // Number of results returned
+	Matched    int                `json:"matched"`    // Submitted addresses with a result
+	Rejected   []EmailBatchError  `json:"rejected"`   // Addresses rejected, with reasons
+	Duplicates []DuplicateGroup   `json:"duplicates"` // Inputs deduplicated by the API
+	Missing    []string           `json:"missing"`    // Submitted addresses with no result and no known reason
+	Unexpected []EmailBatchResult `json:"unexpected"` // Results for addresses that were never submitted
+
+	// UnlistedRejections counts rejections reported by the API that Rejected does not explain.
+	// Those addresses are among Missing.
+	UnlistedRejections int `json:"unlisted_rejections"`
+
+	// Providers summarizes the results by mail provider, filled in by ClassifyProviders
+	Providers []ProviderSummary `json:"providers,omitempty"`
+}
+
+// IsComplete returns true when every submitted address is accounted for
+func (r *BatchReconciliation) IsComplete() bool {
+	return len(r.Missing) == 0
+}
+
+// ReconcileBatch compares the submitted addresses with the results of a batch task and
+// reports rejected addresses with reasons, duplicate mappings and missing entries.
+// Call it once the task is verified; before that, pending addresses show up as missing.
+//
+// Parameters:
+//   - submitted: The addresses passed to ValidateBatch
+//   - submission: The response from ValidateBatch, may be nil if it was not kept
+//   - results: The response from GetBatchResults
+//
+// Returns:
+//   - *BatchReconciliation: The reconciliation report
+func ReconcileBatch(submitted []string, submission *BatchValidateResponse, results *BatchResultResponse) *BatchReconciliation {
+	report := &BatchReconciliation{Submitted: len(submitted)}
+
+	returned := make(map[string]bool)
+	if results != nil {
+		report.Returned = len(results.Results.EmailBatch)
+		for _, result := range results.Results.EmailBatch {
+			returned[NormalizeEmail(result.Address)] = true
+		}
+	}
+
+	rejected := make(map[string]string)
+	if submission != nil {
+		for _, batchError := range submission.Errors {
+			rejected[NormalizeEmail(batchError.Address)] = batchError.Error
+		}
+	}
+
+	// Group the inputs by normalized address, keeping submission order
+	var order []string
+	inputs := make(map[string][]string)
+	for _, email := range submitted {
+		normalized := NormalizeEmail(email)
+		if _, seen := inputs[normalized]; !seen {
+			order = append(order, normalized)
+		}
+		inputs[normalized] = append(inputs[normalized], email)
+	}
+
+	for _, address := range order {
+		group := inputs[address]
+		if len(group) > 1 {
+			report.Duplicates = append(report.Duplicates, DuplicateGroup{Address: address, Inputs: group})
+		}
+
+		switch {
+		case returned[address]:
+			report.Matched += len(group)
+		case rejected[address] != "":
+			report.Rejected = append(report.Rejected, EmailBatchError{Address: group[0], Error: rejected[address]})
+		case CheckEmailSyntax(address) != nil:
+			report.Rejected = append(report.Rejected, EmailBatchError{Address: group[0], Error: REJECT_REASON_SYNTAX})
+		default:
+			report.Missing = append(report.Missing, group[0])
+		}
+	}
+
+	// Rejections the API counted but that nothing above explains are somewhere among Missing
+	if submission != nil && submission.CountRejected > len(report.Rejected) {
+		report.UnlistedRejections = submission.CountRejected - len(report.Rejected)
+	}
+
+	if results != nil {
+		for _, result := range results.Results.EmailBatch {
+			if _, ok := inputs[NormalizeEmail(result.Address)]; !ok {
+				report.Unexpected = append(report.Unexpected, result)
+			}
+		}
+	}
+	return report
+}
@@ -0,0 +1,59 @@
+package emailverifygo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReconcileBatch(t *testing.T) {
+	submitted := []string{
+		"valid@example.com",
+		"Valid@Example.com ",
+		"invalid@example.com",
+		"not-an-email",
+		"blocked@example.com",
+		"lost@example.com",
+	}
+	submission := &BatchValidateResponse{
+		TaskID:        12345,
+		CountRejected: 3,
+		Errors: []EmailBatchError{
+			{Address: "blocked@example.com", Error: "Domain is blocked"},
+		},
+	}
+	results := &BatchResultResponse{
+		TaskID: 12345,
+		Status: BATCH_STATUS_VERIFIED,
+		Results: BatchValidateResultsWrapper{EmailBatch: []EmailBatchResult{
+			{Address: "valid@example.com", Status: STATUS_VALID, SubStatus: SUBSTATUS_PERMITTED},
+			{Address: "invalid@example.com", Status: STATUS_INVALID, SubStatus: SUBSTATUS_MAILBOX_NOT_FOUND},
+			{Address: "stranger@example.com", Status: STATUS_VALID, SubStatus: SUBSTATUS_PERMITTED},
+		}},
+	}
+
+	report := ReconcileBatch(submitted, submission, results)
+
+	assert.Equal(t, 6, report.Submitted, "Expected all inputs counted")
+	assert.Equal(t, 3, report.Returned, "Expected all results counted")
+	assert.Equal(t, 3, report.Matched, "Expected both forms of the duplicate and the invalid address to match")
+	assert.Equal(t, []DuplicateGroup{
+		{Address: "valid@example.com", Inputs: []string{"valid@example.com", "Valid@Example.com "}},
+	}, report.Duplicates, "Expected the case variant to be reported as a duplicate")
+	assert.Equal(t, []EmailBatchError{
+		{Address: "not-an-email", Error: REJECT_REASON_SYNTAX},
+		{Address: "blocked@example.com", Error: "Domain is blocked"},
+	}, report.Rejected, "Expected rejected addresses with reasons")
+	assert.Equal(t, []string{"lost@example.com"}, report.Missing, "Expected the silently dropped address")
+	assert.Equal(t, 1, report.UnlistedRejections, "Expected one rejection the API did not name")
+	assert.Equal(t, "stranger@example.com", report.Unexpected[0].Address, "Expected the unsubmitted result")
+	assert.False(t, report.IsComplete(), "Expected report to be incomplete while an address is missing")
+}
+
+func TestCheckEmailSyntax(t *testing.T) {
+	assert.Nil(t, CheckEmailSyntax("john.doe@example.com"), "Expected a plain address to pass")
+	assert.NotNil(t, CheckEmailSyntax(""), "Expected empty input to fail")
+	assert.NotNil(t, CheckEmailSyntax("john@localhost"), "Expected a dotless domain to fail")
+	assert.NotNil(t, CheckEmailSyntax("John <john@example.com>"), "Expected a display name to fail")
+	assert.NotNil(t, CheckEmailSyntax("john@@example.com"), "Expected a double @ to fail")
+}
@@ -0,0 +1,380 @@
+package emailverifygo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ErrBatchJobNotFound is returned when a job store has no record for a task ID
+var ErrBatchJobNotFound = errors.New("batch job not found")
+
+// BatchJob is the local record of a submitted batch validation task.
+// It holds everything needed to reattach to the task after a restart.
+type BatchJob struct {
+	TaskID      int         `json:"task_id"`
+	Title       string      `json:"title"`
+	Emails      []string    `json:"emails"`
+	Items       []BatchItem `json:"items,omitempty"` // Set when submitted with SubmitBatchItemsJob
+	SubmittedAt time.Time   `json:"submitted_at"`
+	Status      string      `json:"status"`
+	UpdatedAt   time.Time   `json:"updated_at"`
+	Owner       string      `json:"owner,omitempty"` // Who submitted the task, for services sharing one API key
+}
+
+// IsFinished returns true once the task has been fully verified
+func (j *BatchJob) IsFinished() bool {
+	return j.Status == BATCH_STATUS_VERIFIED
+}
+
+// AttachResults joins the job's items with the task results. Jobs submitted
+// without items get one item per submitted address.
+func (j *BatchJob) AttachResults(results *BatchResultResponse) []BatchItemResult {
+	items := j.Items
+	if items == nil {
+		items = make([]BatchItem, len(j.Emails))
+		for i, email := range j.Emails {
+			items[i] = BatchItem{Address: email}
+		}
+	}
+	return AttachBatchItems(items, results)
+}
+
+// BatchJobStore persists batch jobs so their task IDs survive process restarts
+type BatchJobStore interface {
+	Save(job *BatchJob) error
+	Get(taskID int) (*BatchJob, error)
+	List() ([]*BatchJob, error)
+	Delete(taskID int) error
+}
+
+// MemoryBatchJobStore keeps jobs in memory. Useful for tests and short-lived processes.
+type MemoryBatchJobStore struct {
+	mu   sync.Mutex
+	jobs map[int]BatchJob
+}
+
+// NewMemoryBatchJobStore creates an empty in-memory job store
+func NewMemoryBatchJobStore() *MemoryBatchJobStore {
+	return &MemoryBatchJobStore{jobs: make(map[int]BatchJob)}
+}
+
+// Save inserts or replaces the job with the same task ID
+func (s *MemoryBatchJobStore) Save(job *BatchJob) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.jobs[job.TaskID] = *job
+	return nil
+}
+
+// Get returns a copy of the job for the given task ID
+func (s *MemoryBatchJobStore) Get(taskID int) (*BatchJob, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	job, ok := s.jobs[taskID]
+	if !ok {
+		return nil, ErrBatchJobNotFound
+	}
+	return &job, nil
+}
+
+// List returns all jobs ordered by task ID
+func (s *MemoryBatchJobStore) List() ([]*BatchJob, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return sortedJobs(s.jobs), nil
+}
+
+// Delete removes the job for the given task ID
+func (s *MemoryBatchJobStore) Delete(taskID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.jobs, taskID)
+	return nil
+}
+
+// FileBatchJobStore keeps jobs in a single JSON file. It is the default store.
+// Every write replaces the file atomically, so a crash never leaves it half written.
+type FileBatchJobStore struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewFileBatchJobStore creates a job store backed by the file at path.
+// The file is created on the first Save if it does not exist.
+func NewFileBatchJobStore(path string) *FileBatchJobStore {
+	return &FileBatchJobStore{path: path}
+}
+
+// Save inserts or replaces the job with the same task ID
+func (s *FileBatchJobStore) Save(job *BatchJob) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	jobs, err := s.read()
+	if err != nil {
+		return err
+	}
+	jobs[job.TaskID] = *job
+	return s.write(jobs)
+}
+
+// Get returns the job for the given task ID
+func (s *FileBatchJobStore) Get(taskID int) (*BatchJob, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	jobs, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+	job, ok := jobs[taskID]
+	if !ok {
+		return nil, ErrBatchJobNotFound
+	}
+	return &job, nil
+}
+
+// List returns all jobs ordered by task ID
+func (s *FileBatchJobStore) List() ([]*BatchJob, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	jobs, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+	return sortedJobs(jobs), nil
+}
+
+// Delete removes the job for the given task ID
+func (s *FileBatchJobStore) Delete(taskID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	jobs, err := s.read()
+	if err != nil {
+		return err
+	}
+	delete(jobs, taskID)
+	return s.write(jobs)
+}
+
+func (s *FileBatchJobStore) read() (map[int]BatchJob, error) {
+	jobs := make(map[int]BatchJob)
+
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return jobs, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read job store: %w", err)
+	}
+	if len(data) == 0 {
+		return jobs, nil
+	}
+
+	var stored map[string]BatchJob
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return nil, fmt.Errorf("failed to decode job store: %w", err)
+	}
+	for _, job := range stored {
+		jobs[job.TaskID] = job
+	}
+	return jobs, nil
+}
+
+func (s *FileBatchJobStore) write(jobs map[int]BatchJob) error {
+	stored := make(map[string]BatchJob, len(jobs))
+	for id, job := range jobs {
+		stored[strconv.Itoa(id)] = job
+	}
+
+	data, err := json.MarshalIndent(stored, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode job store: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".tmp*")
+	if err != nil {
+		return fmt.Errorf("failed to write job store: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write job store: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write job store: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("failed to write job store: %w", err)
+	}
+	return nil
+}
+
+func sortedJobs(jobs map[int]BatchJob) []*BatchJob {
+	list := make([]*BatchJob, 0, len(jobs))
+	for _, job := range jobs {
+		job := job
+		list = append(list, &job)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].TaskID < list[j].TaskID })
+	return list
+}
+
+// SubmitBatchJob submits a batch with ValidateBatch and records the task in the store
+//
+// Parameters:
+//   - store: The job store to record the task in
+//   - title: The name for this batch validation task
+//   - emails: A slice of email addresses to validate
+//
+// Returns:
+//   - *BatchJob: The recorded job
+//   - *BatchValidateResponse: The initial batch submission response
+//   - error: Any error that occurred during submission or while saving the job
+func SubmitBatchJob(store BatchJobStore, title string, emails []string) (*BatchJob, *BatchValidateResponse, error) {
+	return submitBatchJob(store, title, append([]string(nil), emails...), nil)
+}
+
+// SubmitBatchItemsJob works like SubmitBatchJob but keeps each item's external ID
+// and metadata in the store, so results can be joined back after a restart
+// with BatchJob.AttachResults
+func SubmitBatchItemsJob(store BatchJobStore, title string, items []BatchItem) (*BatchJob, *BatchValidateResponse, error) {
+	return submitBatchJob(store, title, batchItemAddresses(items), append([]BatchItem(nil), items...))
+}
+
+func submitBatchJob(store BatchJobStore, title string, emails []string, items []BatchItem) (*BatchJob, *BatchValidateResponse, error) {
+	response, err := ValidateBatch(title, emails)
+	if err != nil {
+		return nil, response, err
+	}
+
+	now := time.Now()
+	job := &BatchJob{
+		TaskID:      response.TaskID,
+		Title:       title,
+		Emails:      emails,
+		Items:       items,
+		SubmittedAt: now,
+		Status:      BATCH_STATUS_SUBMITTED,
+		UpdatedAt:   now,
+	}
+	logBatchTransition(job.TaskID, "", job.Status)
+	if err := store.Save(job); err != nil {
+		return job, response, fmt.Errorf("batch %d submitted but not saved: %w", job.TaskID, err)
+	}
+	return job, response, nil
+}
+
+// PollBatchJob polls GetBatchResults for the job every interval, or
+// DEFAULT_BATCH_POLL_INTERVAL if interval is not positive, until the task is
+// verified or ctx is done. Transient polling errors (transport failures, 429
+// and 5xx responses) are retried on the next tick and only end polling after
+// DEFAULT_BATCH_MAX_POLL_FAILURES in a row; any other error ends it at once.
+// Status changes are written back to the store.
+func PollBatchJob(ctx context.Context, store BatchJobStore, job *BatchJob, interval time.Duration) (*BatchResultResponse, error) {
+	if interval <= 0 {
+		interval = DEFAULT_BATCH_POLL_INTERVAL
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	failures := 0
+	for {
+		result, err := GetBatchResultsContext(withAttempt(ctx, failures+1), job.TaskID)
+		if err != nil {
+			failures++
+			if failures >= DEFAULT_BATCH_MAX_POLL_FAILURES || ctx.Err() != nil || !pollRetryable(err) {
+				return result, err
+			}
+			select {
+			case <-ctx.Done():
+				return result, ctx.Err()
+			case <-ticker.C:
+			}
+			continue
+		}
+		failures = 0
+		observeBatchPoll(job.TaskID, result.Status)
+
+		if result.Status != "" && result.Status != job.Status {
+			logBatchTransition(job.TaskID, job.Status, result.Status)
+			job.Status = result.Status
+			job.UpdatedAt = time.Now()
+			if err := store.Save(job); err != nil {
+				return result, fmt.Errorf("failed to save batch %d: %w", job.TaskID, err)
+			}
+		}
+		if result.IsComplete() {
+			return result, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return result, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// pollRetryable reports whether a failed poll may succeed on the next tick: the
+// request never got a response, or the API was throttling or failing with a 5xx
+func pollRetryable(err error) bool {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode == http.StatusTooManyRequests || apiErr.StatusCode >= 500
+	}
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+		return false
+	}
+	var urlErr *url.Error
+	return errors.As(err, &urlErr)
+}
+
+// ResumeBatchJobs reattaches to every unfinished job in the store and polls them
+// concurrently until they complete or ctx is done. It is meant to be called on
+// startup after a crash or redeploy.
+//
+// The handler, if not nil, is called once per resumed job with its final results
+// or the error that stopped polling. ResumeBatchJobs returns once all jobs are done.
+func ResumeBatchJobs(ctx context.Context, store BatchJobStore, interval time.Duration, handler func(*BatchJob, *BatchResultResponse, error)) error {
+	jobs, err := store.List()
+	if err != nil {
+		return err
+	}
+
+	var (
+		wg sync.WaitGroup
+		mu sync.Mutex
+	)
+	for _, job := range jobs {
+		if job.IsFinished() {
+			continue
+		}
+
+		wg.Add(1)
+		go func(job *BatchJob) {
+			defer wg.Done()
+			result, err := PollBatchJob(ctx, store, job, interval)
+			if handler != nil {
+				mu.Lock()
+				handler(job, result, err)
+				mu.Unlock()
+			}
+		}(job)
+	}
+	wg.Wait()
+	return nil
+}
@@ -0,0 +1,153 @@
+package emailverifygo
+
+import (
+	"context"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBatchJobStore(t *testing.T) {
+	t.Run("TestFileStoreSurvivesReopen", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "jobs.json")
+		store := NewFileBatchJobStore(path)
+
+		job := &BatchJob{TaskID: 7, Title: "Leads", Emails: []string{"a@example.com"}, Status: BATCH_STATUS_SUBMITTED}
+		assert.Nil(t, store.Save(job), "Expected no error saving job")
+
+		reopened := NewFileBatchJobStore(path)
+		loaded, err := reopened.Get(7)
+		assert.Nil(t, err, "Expected no error loading job")
+		assert.Equal(t, "Leads", loaded.Title, "Expected title to survive reopen")
+		assert.Equal(t, []string{"a@example.com"}, loaded.Emails, "Expected emails to survive reopen")
+
+		assert.Nil(t, reopened.Delete(7), "Expected no error deleting job")
+		_, err = store.Get(7)
+		assert.ErrorIs(t, err, ErrBatchJobNotFound, "Expected job to be gone")
+	})
+
+	t.Run("TestFileStoreMissingFile", func(t *testing.T) {
+		store := NewFileBatchJobStore(filepath.Join(t.TempDir(), "missing.json"))
+		jobs, err := store.List()
+		assert.Nil(t, err, "Expected a missing file to be an empty store")
+		assert.Empty(t, jobs, "Expected no jobs")
+	})
+}
+
+func TestResumeBatchJobs(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	SetApiKey("test_api_key")
+
+	httpmock.RegisterResponder("POST", `=~^(.*)`+ENDPOINT_VALIDATE_BATCH+`(.*)\z`,
+		httpmock.NewStringResponder(200, MOCK_BATCH_RESPONSE))
+
+	polls := 0
+	httpmock.RegisterResponder("GET", `=~^(.*)`+ENDPOINT_BATCH_RESULT+`(.*)\z`,
+		func(r *http.Request) (*http.Response, error) {
+			polls++
+			if polls < 3 {
+				return httpmock.NewStringResponse(200, MOCK_BATCH_IN_PROGRESS_RESPONSE), nil
+			}
+			return httpmock.NewStringResponse(200, MOCK_BATCH_RESULTS_RESPONSE), nil
+		},
+	)
+
+	path := filepath.Join(t.TempDir(), "jobs.json")
+	job, _, err := SubmitBatchJob(NewFileBatchJobStore(path), "Test Batch", []string{"valid@example.com"})
+	assert.Nil(t, err, "Expected no error submitting")
+	assert.Equal(t, 12345, job.TaskID, "Expected task ID from the API")
+
+	// Simulate a restart by opening the store again
+	store := NewFileBatchJobStore(path)
+	finished := map[int]*BatchResultResponse{}
+	err = ResumeBatchJobs(context.Background(), store, time.Millisecond, func(job *BatchJob, result *BatchResultResponse, err error) {
+		assert.Nil(t, err, "Expected no polling error")
+		finished[job.TaskID] = result
+	})
+	assert.Nil(t, err, "Expected no error resuming")
+	assert.Equal(t, 3, polls, "Expected polling to continue until verified")
+	assert.Equal(t, 3, len(finished[12345].Results.EmailBatch), "Expected final results for the resumed task")
+
+	stored, err := store.Get(12345)
+	assert.Nil(t, err, "Expected job to still be stored")
+	assert.True(t, stored.IsFinished(), "Expected stored job to be marked verified")
+
+	// Finished jobs are not polled again
+	err = ResumeBatchJobs(context.Background(), store, time.Millisecond, nil)
+	assert.Nil(t, err, "Expected no error resuming")
+	assert.Equal(t, 3, polls, "Expected no further polling")
+}
+
+func TestPollBatchJobFailures(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	SetApiKey("test_api_key")
+
+	failing, failStatus := 0, 500
+	httpmock.RegisterResponder("GET", `=~^(.*)`+ENDPOINT_BATCH_RESULT+`(.*)\z`,
+		func(r *http.Request) (*http.Response, error) {
+			if failing > 0 {
+				failing--
+				return httpmock.NewStringResponse(failStatus, MOCK_ERROR_RESPONSE), nil
+			}
+			return httpmock.NewStringResponse(200, MOCK_BATCH_RESULTS_RESPONSE), nil
+		},
+	)
+
+	store := NewMemoryBatchJobStore()
+	job := &BatchJob{TaskID: 12345, Status: BATCH_STATUS_SUBMITTED}
+	store.Save(job)
+
+	t.Run("TestTransientErrorsRetried", func(t *testing.T) {
+		failing = DEFAULT_BATCH_MAX_POLL_FAILURES - 1
+		result, err := PollBatchJob(context.Background(), store, job, time.Millisecond)
+
+		assert.Nil(t, err, "Expected polling to survive errors below the limit")
+		assert.True(t, result.IsComplete())
+	})
+
+	t.Run("TestFailureLimit", func(t *testing.T) {
+		httpmock.ZeroCallCounters()
+		failing = DEFAULT_BATCH_MAX_POLL_FAILURES
+		_, err := PollBatchJob(context.Background(), store, job, time.Millisecond)
+
+		assert.NotNil(t, err, "Expected polling to stop at the failure limit")
+		assert.Equal(t, DEFAULT_BATCH_MAX_POLL_FAILURES, httpmock.GetTotalCallCount())
+	})
+
+	t.Run("TestThrottlingRetried", func(t *testing.T) {
+		failing, failStatus = 2, 429
+		defer func() { failStatus = 500 }()
+		_, err := PollBatchJob(context.Background(), store, job, time.Millisecond)
+
+		assert.Nil(t, err, "Expected throttling to be retried")
+	})
+
+	t.Run("TestClientErrorsNotRetried", func(t *testing.T) {
+		for _, status := range []int{401, 403, 404} {
+			httpmock.ZeroCallCounters()
+			failing, failStatus = DEFAULT_BATCH_MAX_POLL_FAILURES, status
+			_, err := PollBatchJob(context.Background(), store, job, time.Hour)
+
+			var apiErr *APIError
+			if assert.ErrorAs(t, err, &apiErr, "Expected the API error for %d", status) {
+				assert.Equal(t, status, apiErr.StatusCode)
+			}
+			assert.Equal(t, 1, httpmock.GetTotalCallCount(), "Expected a %d to end polling at once", status)
+		}
+		failing, failStatus = 0, 500
+	})
+
+	t.Run("TestNonPositiveInterval", func(t *testing.T) {
+		assert.NotPanics(t, func() {
+			PollBatchJob(context.Background(), store, job, 0)
+		}, "Expected the default interval to be used")
+	})
+}
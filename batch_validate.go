@@ -1,12 +1,19 @@
 package emailverifygo
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/url"
 	"strings"
 )
 
+// Batch task status constants
+const (
+	BATCH_STATUS_SUBMITTED = "submitted" // Local status before any results have been polled
+	BATCH_STATUS_VERIFIED  = "verified"  // The task finished and all results are available
+)
+
 // EmailAddress represents one email address unit to be validated in a batch
 type EmailAddress struct {
 	Address string `json:"address"`
@@ -17,6 +24,9 @@ type EmailBatchResult struct {
 	Address   string `json:"address"`
 	Status    string `json:"status"`
 	SubStatus string `json:"sub_status"`
+	// Provider is the mail provider of the address's domain, set when
+	// SetProviderDetection is on or by BatchReconciliation.ClassifyProviders
+	Provider *ProviderClassification `json:"provider,omitempty"`
 }
 
 // EmailBatchError an error unit received in the response, that can be associated
@@ -29,7 +39,7 @@ type EmailBatchError struct {
 // BatchValidateRequest represents the structure of the batch validation request
 type BatchValidateRequest struct {
 	Title      string        `json:"title"`
-	Key        string        `json:"key"`
+	Key        string        `json:"key,omitempty"` // Empty when the key is sent in a header
 	EmailBatch []EmailAddress `json:"email_batch"`
 }
 
@@ -46,6 +56,7 @@ type BatchValidateResponse struct {
 	CountDuplicatesRemoved     int               `json:"count_duplicates_removed,omitempty"` // For initial submit response
 	CountRejected       int               `json:"count_rejected_emails,omitempty"` // For initial submit response
 	CountProcessing     int               `json:"count_processing,omitempty"` // For initial submit response
+	Errors              []EmailBatchError `json:"errors,omitempty"` // Rejected addresses, when the API lists them
 }
 
 type BatchResultResponse struct {
@@ -58,6 +69,24 @@ type BatchResultResponse struct {
 	Results             BatchValidateResultsWrapper `json:"results,omitempty"`
 }
 
+// CreditsCharged estimates the credits the submitted batch will consume:
+// the addresses being processed, or those left after duplicates and rejections
+func (b *BatchValidateResponse) CreditsCharged() int {
+	if b.CountProcessing > 0 {
+		return b.CountProcessing
+	}
+	charged := b.CountSubmitted - b.CountDuplicatesRemoved - b.CountRejected
+	if charged < 0 {
+		return 0
+	}
+	return charged
+}
+
+// IsComplete returns true once the batch task has been fully verified
+func (b *BatchResultResponse) IsComplete() bool {
+	return b.Status == BATCH_STATUS_VERIFIED
+}
+
 // ValidateBatch submits a batch of emails for Verification
 //
 // Parameters:
@@ -70,7 +99,17 @@ type BatchResultResponse struct {
 //
 // API Reference: POST /api/v1/validate-batch
 func ValidateBatch(title string, emails []string) (*BatchValidateResponse, error) {
-	response := &BatchValidateResponse{}
+	return ValidateBatchContext(context.Background(), title, emails)
+}
+
+// ValidateBatchContext is like ValidateBatch but carries ctx to the HTTP request and tracing spans
+func ValidateBatchContext(ctx context.Context, title string, emails []string) (response *BatchValidateResponse, err error) {
+	ctx, span := startSpan(ctx, SPAN_VALIDATE_BATCH, Attr("endpoint", ENDPOINT_VALIDATE_BATCH), Attr("batch.size", len(emails)))
+	defer func() {
+		finishSpan(span, err)
+	}()
+
+	response = &BatchValidateResponse{}
 	
 	if title == "" {
         return response, fmt.Errorf("Title is required")
@@ -90,9 +129,11 @@ func ValidateBatch(title string, emails []string) (*BatchValidateResponse, error
 	// Create the request payload
 	requestData := BatchValidateRequest{
 		Title:      title,
-		Key:        API_KEY,
 		EmailBatch: emailBatch,
 	}
+	if API_KEY_HEADER == "" {
+		requestData.Key = API_KEY
+	}
 	
 	// Convert the request data to JSON
 	requestBody := &strings.Builder{}
@@ -107,7 +148,16 @@ func ValidateBatch(title string, emails []string) (*BatchValidateResponse, error
 	}
 	
 	// Make the POST request
-	err = DoPostRequest(urlToRequest, strings.NewReader(requestBody.String()), response)
+	err = doPostRequest(ctx, urlToRequest, strings.NewReader(requestBody.String()), response)
+	if err == nil {
+		observeCredits(ENDPOINT_VALIDATE_BATCH, response.CreditsCharged())
+		span.SetAttributes(
+			Attr("task_id", response.TaskID),
+			Attr("count_submitted", response.CountSubmitted),
+			Attr("count_rejected", response.CountRejected),
+			Attr("count_duplicates_removed", response.CountDuplicatesRemoved),
+		)
+	}
 	return response, err
 }
 
@@ -122,7 +172,17 @@ func ValidateBatch(title string, emails []string) (*BatchValidateResponse, error
 //
 // API Reference: GET /api/v1/get-result-bulk-verification-task
 func GetBatchResults(taskID int) (*BatchResultResponse, error) {
-	response := &BatchResultResponse{}
+	return GetBatchResultsContext(context.Background(), taskID)
+}
+
+// GetBatchResultsContext is like GetBatchResults but carries ctx to the HTTP request and tracing spans
+func GetBatchResultsContext(ctx context.Context, taskID int) (response *BatchResultResponse, err error) {
+	ctx, span := startSpan(ctx, SPAN_GET_BATCH_RESULTS, Attr("endpoint", ENDPOINT_BATCH_RESULT), Attr("task_id", taskID))
+	defer func() {
+		finishSpan(span, err)
+	}()
+
+	response = &BatchResultResponse{}
 
 	if taskID <= 0 {
 		return response, fmt.Errorf("TaskID must be greater than 0")
@@ -139,6 +199,36 @@ func GetBatchResults(taskID int) (*BatchResultResponse, error) {
 	}
 	
 	// Make the request
-	err = DoGetRequest(url_to_request, response)
+	err = doGetRequest(ctx, url_to_request, response)
+	if err == nil {
+		span.SetAttributes(Attr("status", response.Status), Attr("progress_percentage", response.ProgressPercentage))
+		span.SetAttributes(subStatusAttributes(response.Results.EmailBatch)...)
+		for _, result := range response.Results.EmailBatch {
+			observeDomain(result.Address, result.Status, result.SubStatus)
+		}
+		if response.IsComplete() {
+			attachBatchProviders(ctx, response.Results.EmailBatch)
+		}
+	}
 	return response, err
 }
+
+// subStatusAttributes counts results per status and sub-status, e.g. "results.status.valid"
+func subStatusAttributes(results []EmailBatchResult) []Attribute {
+	counts := make(map[string]int)
+	var keys []string
+	for _, result := range results {
+		for _, key := range []string{"results.status." + result.Status, "results.sub_status." + result.SubStatus} {
+			if counts[key] == 0 {
+				keys = append(keys, key)
+			}
+			counts[key]++
+		}
+	}
+
+	attrs := make([]Attribute, len(keys))
+	for i, key := range keys {
+		attrs[i] = Attr(key, counts[key])
+	}
+	return attrs
+}
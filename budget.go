@@ -0,0 +1,279 @@
+package emailverifygo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DEFAULT_BUDGET_REFRESH_INTERVAL is how often queued work re-checks the account balance
+const DEFAULT_BUDGET_REFRESH_INTERVAL = time.Minute
+
+// ErrBudgetExceeded is returned when a call would spend more credits than the budget allows
+var ErrBudgetExceeded = errors.New("credit budget exceeded")
+
+// BudgetOptions configures a BudgetController
+type BudgetOptions struct {
+	// Cap is the most credits the controller may ever spend, 0 for no cap
+	Cap int
+	// Reserve is the number of credits always left untouched on the account
+	Reserve int
+	// Queue makes the wrapped calls wait for credits instead of failing with ErrBudgetExceeded
+	Queue bool
+	// RefreshInterval is how often queued calls re-fetch the balance, e.g. to see a daily reset
+	RefreshInterval time.Duration
+}
+
+// BudgetController guards credit spending. It fetches the account balance,
+// tracks what calls are expected to consume since then, and refuses or queues
+// work that would go over the cap, dip into the reserve or exceed the credits
+// available today. Whenever the balance reports a daily credits limit, the
+// credits consumed through the controller each UTC day are held to it, on
+// every plan; AppSumo bonus credits come on top of the limit.
+type BudgetController struct {
+	opts BudgetOptions
+
+	mu         sync.Mutex
+	balance    *AccountBalanceResponse
+	available  int    // Credits available according to the last balance fetch
+	consumed   int    // Credits consumed since the last balance fetch
+	pending    int    // Credits reserved by calls in flight
+	spent      int    // Credits consumed since the controller was created
+	day        string // The UTC day spentToday counts
+	spentToday int    // Credits consumed during day
+	changed    chan struct{}
+	now        func() time.Time
+}
+
+// NewBudgetController creates a controller. Call Refresh before use, or let the
+// first reservation fetch the balance.
+func NewBudgetController(opts BudgetOptions) *BudgetController {
+	if opts.RefreshInterval <= 0 {
+		opts.RefreshInterval = DEFAULT_BUDGET_REFRESH_INTERVAL
+	}
+	return &BudgetController{opts: opts, changed: make(chan struct{}), now: time.Now}
+}
+
+// Refresh fetches the account balance and resets the local consumption estimate
+func (b *BudgetController) Refresh(ctx context.Context) error {
+	balance, err := GetAccountBalanceContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to refresh balance: %w", err)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.balance = balance
+	b.available = balance.CreditsAvailable()
+	b.consumed = 0
+	b.notify()
+	return nil
+}
+
+// Balance returns the balance from the last refresh, nil before the first one
+func (b *BudgetController) Balance() *AccountBalanceResponse {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.balance
+}
+
+// Remaining returns how many more credits may be reserved right now
+func (b *BudgetController) Remaining() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.remainingLocked()
+}
+
+// Spent returns the credits consumed through the controller so far
+func (b *BudgetController) Spent() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.spent
+}
+
+func (b *BudgetController) remainingLocked() int {
+	remaining := b.available - b.consumed - b.pending - b.opts.Reserve
+	if b.opts.Cap > 0 {
+		if capped := b.opts.Cap - b.spent - b.pending; capped < remaining {
+			remaining = capped
+		}
+	}
+	if limit, ok := b.dailyLimitLocked(); ok {
+		b.rolloverLocked()
+		if daily := limit - b.spentToday - b.pending; daily < remaining {
+			remaining = daily
+		}
+	}
+	if remaining < 0 {
+		return 0
+	}
+	return remaining
+}
+
+// dailyLimitLocked returns the credits the controller may consume per UTC day,
+// if the last balance reported a daily limit
+func (b *BudgetController) dailyLimitLocked() (int, bool) {
+	if b.balance == nil || !b.balance.DailyCreditsLimit.Valid {
+		return 0, false
+	}
+	return int(b.balance.DailyCreditsLimit.Int64 + b.balance.BonusCredits.ValueOrZero()), true
+}
+
+// rolloverLocked starts counting a new UTC day's consumption when the day changes
+func (b *BudgetController) rolloverLocked() {
+	if day := b.now().UTC().Format("2006-01-02"); day != b.day {
+		b.day = day
+		b.spentToday = 0
+	}
+}
+
+// BudgetReservation holds credits for one call until it is committed or released
+type BudgetReservation struct {
+	controller *BudgetController
+	credits    int
+	done       bool
+}
+
+// Commit records that the call consumed the given credits and releases the rest
+func (r *BudgetReservation) Commit(consumed int) {
+	b := r.controller
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if r.done {
+		return
+	}
+	r.done = true
+	b.pending -= r.credits
+	b.consumed += consumed
+	b.spent += consumed
+	b.rolloverLocked()
+	b.spentToday += consumed
+	b.notify()
+}
+
+// Release gives back the reserved credits, for calls that consumed nothing
+func (r *BudgetReservation) Release() {
+	r.Commit(0)
+}
+
+// TryReserve reserves credits if the budget allows it right now, or fails with ErrBudgetExceeded
+func (b *BudgetController) TryReserve(ctx context.Context, credits int) (*BudgetReservation, error) {
+	if b.Balance() == nil {
+		if err := b.Refresh(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if credits > b.remainingLocked() {
+		return nil, fmt.Errorf("%w: %d credits needed, %d available", ErrBudgetExceeded, credits, b.remainingLocked())
+	}
+	b.pending += credits
+	return &BudgetReservation{controller: b, credits: credits}, nil
+}
+
+// Reserve waits until the credits can be reserved or ctx is done. Credits come
+// back when other reservations are released or a balance refresh shows more,
+// e.g. after a daily reset. It fails at once if the cap can never allow it.
+func (b *BudgetController) Reserve(ctx context.Context, credits int) (*BudgetReservation, error) {
+	if b.opts.Cap > 0 && credits > b.opts.Cap {
+		return nil, fmt.Errorf("%w: %d credits needed, cap is %d", ErrBudgetExceeded, credits, b.opts.Cap)
+	}
+
+	timer := time.NewTimer(b.opts.RefreshInterval)
+	defer timer.Stop()
+
+	for {
+		b.mu.Lock()
+		changed := b.changed
+		capExhausted := b.opts.Cap > 0 && b.spent+credits > b.opts.Cap
+		limit, limited := b.dailyLimitLocked()
+		b.mu.Unlock()
+		if capExhausted {
+			return nil, fmt.Errorf("%w: cap of %d credits reached", ErrBudgetExceeded, b.opts.Cap)
+		}
+		if limited && credits > limit {
+			return nil, fmt.Errorf("%w: %d credits needed, daily limit is %d", ErrBudgetExceeded, credits, limit)
+		}
+
+		reservation, err := b.TryReserve(ctx, credits)
+		if !errors.Is(err, ErrBudgetExceeded) {
+			return reservation, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-changed:
+		case <-timer.C:
+			if err := b.Refresh(ctx); err != nil {
+				return nil, err
+			}
+			timer.Reset(b.opts.RefreshInterval)
+		}
+	}
+}
+
+func (b *BudgetController) reserve(ctx context.Context, credits int) (*BudgetReservation, error) {
+	if b.opts.Queue {
+		return b.Reserve(ctx, credits)
+	}
+	return b.TryReserve(ctx, credits)
+}
+
+// Validate validates a single address if the budget allows it
+func (b *BudgetController) Validate(ctx context.Context, email string) (*ValidateResponse, error) {
+	reservation, err := b.reserve(ctx, CREDITS_PER_VALIDATION)
+	if err != nil {
+		return nil, err
+	}
+
+	response, credits, err := validateContext(ctx, email)
+	if err != nil {
+		reservation.Release()
+		return response, err
+	}
+	reservation.Commit(credits)
+	return response, nil
+}
+
+// ValidateBatch submits a batch if the budget allows one credit per address
+func (b *BudgetController) ValidateBatch(ctx context.Context, title string, emails []string) (*BatchValidateResponse, error) {
+	reservation, err := b.reserve(ctx, len(emails))
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := ValidateBatchContext(ctx, title, emails)
+	if err != nil {
+		reservation.Release()
+		return response, err
+	}
+	reservation.Commit(response.CreditsCharged())
+	return response, nil
+}
+
+// FindEmail runs an email finder lookup if the budget allows it
+func (b *BudgetController) FindEmail(ctx context.Context, name, domain string) (*FindEmailResponse, error) {
+	reservation, err := b.reserve(ctx, CREDITS_PER_FINDER)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := FindEmailContext(ctx, name, domain)
+	if err != nil {
+		reservation.Release()
+		return response, err
+	}
+	reservation.Commit(CREDITS_PER_FINDER)
+	return response, nil
+}
+
+// notify wakes up queued reservations. Callers must hold b.mu.
+func (b *BudgetController) notify() {
+	close(b.changed)
+	b.changed = make(chan struct{})
+}
@@ -0,0 +1,141 @@
+package emailverifygo
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBudgetController(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	SetApiKey("test_api_key")
+
+	var balance atomic.Value
+	balance.Store(MOCK_ACCOUNT_BALANCE_RESPONSE)
+	httpmock.RegisterResponder("GET", `=~^(.*)`+ENDPOINT_ACCOUNT_BALANCE+`(.*)\z`,
+		func(r *http.Request) (*http.Response, error) {
+			return httpmock.NewStringResponse(200, balance.Load().(string)), nil
+		},
+	)
+	httpmock.RegisterResponder("GET", `=~^(.*)`+ENDPOINT_VALIDATE+`(.*)\z`,
+		func(r *http.Request) (*http.Response, error) {
+			if r.URL.Query().Get("email") == "down@example.com" {
+				return httpmock.NewStringResponse(500, MOCK_ERROR_RESPONSE), nil
+			}
+			return httpmock.NewStringResponse(200, MOCK_VALID_RESPONSE), nil
+		},
+	)
+	httpmock.RegisterResponder("POST", `=~^(.*)`+ENDPOINT_VALIDATE_BATCH+`(.*)\z`,
+		httpmock.NewStringResponder(200, MOCK_BATCH_RESPONSE))
+
+	ctx := context.Background()
+
+	t.Run("TestCap", func(t *testing.T) {
+		budget := NewBudgetController(BudgetOptions{Cap: 2})
+
+		_, err := budget.Validate(ctx, "valid@example.com")
+		assert.Nil(t, err, "Expected the first credit to be allowed")
+		_, err = budget.Validate(ctx, "valid@example.com")
+		assert.Nil(t, err, "Expected the second credit to be allowed")
+
+		httpmock.ZeroCallCounters()
+		_, err = budget.Validate(ctx, "valid@example.com")
+		assert.ErrorIs(t, err, ErrBudgetExceeded, "Expected the cap to refuse the third credit")
+		assert.Equal(t, 0, httpmock.GetTotalCallCount(), "Expected no API call once the cap is reached")
+		assert.Equal(t, 2, budget.Spent(), "Expected two credits spent")
+	})
+
+	t.Run("TestReserve", func(t *testing.T) {
+		budget := NewBudgetController(BudgetOptions{Reserve: 14999})
+
+		_, err := budget.Validate(ctx, "valid@example.com")
+		assert.Nil(t, err, "Expected the one credit above the reserve to be allowed")
+		_, err = budget.Validate(ctx, "valid@example.com")
+		assert.ErrorIs(t, err, ErrBudgetExceeded, "Expected the reserve to be protected")
+	})
+
+	t.Run("TestFailedCallReleasesCredits", func(t *testing.T) {
+		budget := NewBudgetController(BudgetOptions{Cap: 1})
+
+		_, err := budget.Validate(ctx, "down@example.com")
+		assert.NotNil(t, err, "Expected the API error")
+		assert.Equal(t, 1, budget.Remaining(), "Expected the credit to be released")
+	})
+
+	t.Run("TestFreeAnswersNotCharged", func(t *testing.T) {
+		defer SetDomainIntelligence(nil)
+		intelligence := NewDomainIntelligence(DomainIntelligenceOptions{SkipDead: true})
+		intelligence.Observe("anyone@dead.com", STATUS_INVALID, SUBSTATUS_NO_DNS_ENTRIES)
+		SetDomainIntelligence(intelligence)
+		budget := NewBudgetController(BudgetOptions{Cap: 1})
+
+		for i := 0; i < 3; i++ {
+			_, err := budget.Validate(ctx, "someone@dead.com")
+			assert.Nil(t, err, "Expected answers from domain intelligence to fit the budget")
+		}
+		assert.Equal(t, 0, budget.Spent(), "Expected no credit for answers given without a request")
+
+		_, err := budget.Validate(ctx, "valid@example.com")
+		assert.Nil(t, err, "Expected the credit to still be available")
+		assert.Equal(t, 1, budget.Spent())
+	})
+
+	t.Run("TestDailyLimitOnRegularPlan", func(t *testing.T) {
+		now := time.Date(2026, 10, 18, 23, 0, 0, 0, time.UTC)
+		budget := NewBudgetController(BudgetOptions{})
+		budget.now = func() time.Time { return now }
+		assert.Nil(t, budget.Refresh(ctx), "Expected no error refreshing")
+		assert.Equal(t, 150, budget.Remaining(), "Expected the daily limit to bound the 15000 remaining credits")
+
+		reservation, err := budget.TryReserve(ctx, 150)
+		assert.Nil(t, err, "Expected the whole daily limit to be reservable")
+		reservation.Commit(150)
+		_, err = budget.TryReserve(ctx, 1)
+		assert.ErrorIs(t, err, ErrBudgetExceeded, "Expected the daily limit to refuse more credits today")
+
+		_, err = budget.Reserve(ctx, 151)
+		assert.ErrorIs(t, err, ErrBudgetExceeded, "Expected a reservation above the daily limit to fail at once")
+
+		now = now.Add(2 * time.Hour)
+		assert.Nil(t, budget.Refresh(ctx), "Expected no error refreshing")
+		_, err = budget.TryReserve(ctx, 1)
+		assert.Nil(t, err, "Expected a new UTC day to allow credits again")
+	})
+
+	t.Run("TestDailyLimitQueues", func(t *testing.T) {
+		balance.Store(`{"api_status": "enabled", "daily_credits_limit": 150, "remaining_daily_credits": 2}`)
+		defer balance.Store(MOCK_ACCOUNT_BALANCE_RESPONSE)
+
+		budget := NewBudgetController(BudgetOptions{RefreshInterval: 10 * time.Millisecond})
+		_, err := budget.ValidateBatch(ctx, "Test Batch", []string{"a@example.com", "b@example.com", "c@example.com"})
+		assert.ErrorIs(t, err, ErrBudgetExceeded, "Expected the daily limit to refuse the batch")
+
+		queued := NewBudgetController(BudgetOptions{Queue: true, RefreshInterval: 10 * time.Millisecond})
+		assert.Nil(t, queued.Refresh(ctx), "Expected no error refreshing")
+
+		done := make(chan error)
+		go func() {
+			_, err := queued.ValidateBatch(ctx, "Test Batch", []string{"a@example.com", "b@example.com", "c@example.com"})
+			done <- err
+		}()
+
+		// The daily allowance resets
+		time.Sleep(20 * time.Millisecond)
+		balance.Store(`{"api_status": "enabled", "daily_credits_limit": 150, "remaining_daily_credits": 150}`)
+
+		select {
+		case err := <-done:
+			assert.Nil(t, err, "Expected the queued batch to run after the reset")
+		case <-time.After(5 * time.Second):
+			t.Fatal("Expected the queued batch to run after the reset")
+		}
+		assert.Equal(t, 3, queued.Spent(), "Expected the batch credits to be tracked")
+	})
+}
@@ -0,0 +1,271 @@
+package emailverifygo
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Default settings used by FindEmails when an option is left at zero
+const (
+	DEFAULT_FINDER_CONCURRENCY      = 5
+	DEFAULT_FINDER_REQUEST_INTERVAL = 200 * time.Millisecond
+)
+
+// Columns appended to the rows written by WriteFinderCSV
+const (
+	FINDER_COLUMN_EMAIL  = "email"
+	FINDER_COLUMN_STATUS = "status"
+	FINDER_COLUMN_ERROR  = "error"
+)
+
+// FINDER_STATUS_ERROR is written to the status column when a lookup failed
+const FINDER_STATUS_ERROR = "error"
+
+// FinderRecord is one person to look up. Either FullName or FirstName and
+// LastName, and either Domain or CompanyURL, must be set.
+type FinderRecord struct {
+	FullName   string
+	FirstName  string
+	LastName   string
+	Domain     string
+	CompanyURL string
+	Row        []string // The original CSV row, written back with the results
+}
+
+// Name returns the name to search for
+func (r FinderRecord) Name() string {
+	if name := strings.TrimSpace(r.FullName); name != "" {
+		return name
+	}
+	return strings.TrimSpace(strings.TrimSpace(r.FirstName) + " " + strings.TrimSpace(r.LastName))
+}
+
+// LookupDomain returns the domain to search on, taken from CompanyURL when Domain is empty
+func (r FinderRecord) LookupDomain() (string, error) {
+	if domain := strings.TrimSpace(r.Domain); domain != "" {
+		return ExtractDomain(domain)
+	}
+	if r.CompanyURL == "" {
+		return "", fmt.Errorf("no domain or company URL")
+	}
+	return ExtractDomain(r.CompanyURL)
+}
+
+// FinderResult is the outcome of looking up one FinderRecord
+type FinderResult struct {
+	Record   FinderRecord
+	Response *FindEmailResponse // Nil when the lookup failed
+	Err      error
+}
+
+// BulkFinderOptions configures FindEmails
+type BulkFinderOptions struct {
+	// Concurrency is how many lookups run at once
+	Concurrency int
+	// RequestInterval is the minimum spacing between any two lookups
+	RequestInterval time.Duration
+	// OnResult is called as each lookup finishes, from the worker goroutines
+	OnResult func(index int, result FinderResult)
+}
+
+// FindEmails looks up many people concurrently, keeping under the rate limit.
+// Records without a name or domain fail without calling the API.
+//
+// Parameters:
+//   - ctx: Stops lookups not yet started when done
+//   - records: The people to look up
+//   - opts: Concurrency, spacing and progress callback
+//
+// Returns:
+//   - []FinderResult: One result per record, in input order
+func FindEmails(ctx context.Context, records []FinderRecord, opts BulkFinderOptions) []FinderResult {
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = DEFAULT_FINDER_CONCURRENCY
+	}
+	if opts.RequestInterval <= 0 {
+		opts.RequestInterval = DEFAULT_FINDER_REQUEST_INTERVAL
+	}
+	limiter := newRateLimiter(opts.RequestInterval)
+
+	results := make([]FinderResult, len(records))
+	indexes := make(chan int)
+	var workers sync.WaitGroup
+	for i := 0; i < opts.Concurrency; i++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for index := range indexes {
+				results[index] = findRecord(ctx, limiter, records[index])
+				if opts.OnResult != nil {
+					opts.OnResult(index, results[index])
+				}
+			}
+		}()
+	}
+	for index := range records {
+		indexes <- index
+	}
+	close(indexes)
+	workers.Wait()
+	return results
+}
+
+func findRecord(ctx context.Context, limiter *rateLimiter, record FinderRecord) FinderResult {
+	result := FinderResult{Record: record}
+
+	name := record.Name()
+	if name == "" {
+		result.Err = fmt.Errorf("no name")
+		return result
+	}
+	domain, err := record.LookupDomain()
+	if err != nil {
+		result.Err = err
+		return result
+	}
+
+	if err := limiter.Wait(ctx); err != nil {
+		result.Err = err
+		return result
+	}
+	result.Response, result.Err = FindEmailContext(ctx, name, domain)
+	if result.Err != nil {
+		result.Response = nil
+	}
+	return result
+}
+
+// Header names recognised by ReadFinderCSV, compared case-insensitively with
+// spaces and hyphens read as underscores
+var finderCSVHeaders = map[string][]string{
+	"full_name":   {"name", "full_name", "fullname"},
+	"first_name":  {"first_name", "firstname", "first", "given_name"},
+	"last_name":   {"last_name", "lastname", "last", "surname", "family_name"},
+	"domain":      {"domain", "company_domain", "email_domain"},
+	"company_url": {"company_url", "website", "url", "company_website"},
+}
+
+// ReadFinderCSV reads finder records from CSV with a header row. Name columns
+// are read from name/full_name or first_name and last_name, the domain from
+// domain or company_url/website. Each record keeps its original row.
+//
+// Returns:
+//   - []FinderRecord: The records, in file order
+//   - []string: The header row
+//   - error: Any error reading the file, or missing name or domain columns
+func ReadFinderCSV(r io.Reader) ([]FinderRecord, []string, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil, fmt.Errorf("CSV has no header row")
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+
+	columns := make(map[string]int)
+	for i, name := range header {
+		name = strings.NewReplacer(" ", "_", "-", "_").Replace(strings.ToLower(strings.TrimSpace(name)))
+		for field, aliases := range finderCSVHeaders {
+			for _, alias := range aliases {
+				if _, taken := columns[field]; name == alias && !taken {
+					columns[field] = i
+				}
+			}
+		}
+	}
+	_, hasFull := columns["full_name"]
+	_, hasFirst := columns["first_name"]
+	_, hasDomain := columns["domain"]
+	_, hasURL := columns["company_url"]
+	if !hasFull && !hasFirst {
+		return nil, nil, fmt.Errorf("CSV needs a name or first_name column")
+	}
+	if !hasDomain && !hasURL {
+		return nil, nil, fmt.Errorf("CSV needs a domain or company_url column")
+	}
+
+	var records []FinderRecord
+	for {
+		row, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read CSV: %w", err)
+		}
+
+		field := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(row) {
+				return strings.TrimSpace(row[i])
+			}
+			return ""
+		}
+		records = append(records, FinderRecord{
+			FullName:   field("full_name"),
+			FirstName:  field("first_name"),
+			LastName:   field("last_name"),
+			Domain:     field("domain"),
+			CompanyURL: field("company_url"),
+			Row:        row,
+		})
+	}
+	return records, header, nil
+}
+
+// WriteFinderCSV writes each result's original row followed by email, status
+// and error columns
+func WriteFinderCSV(w io.Writer, header []string, results []FinderResult) error {
+	writer := csv.NewWriter(w)
+
+	columns := append(append([]string(nil), header...), FINDER_COLUMN_EMAIL, FINDER_COLUMN_STATUS, FINDER_COLUMN_ERROR)
+	if err := writer.Write(columns); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
+
+	for _, result := range results {
+		row := append([]string(nil), result.Record.Row...)
+		for len(row) < len(header) {
+			row = append(row, "")
+		}
+
+		var email, status, message string
+		switch {
+		case result.Err != nil:
+			status = FINDER_STATUS_ERROR
+			message = RedactAPIKey(result.Err.Error())
+		case result.Response != nil:
+			status = result.Response.Status
+			if result.Response.IsFound() {
+				email = result.Response.Email
+			}
+		}
+		if err := writer.Write(append(row, email, status, message)); err != nil {
+			return fmt.Errorf("failed to write CSV: %w", err)
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
+	return nil
+}
+
+// FindEmailsCSV reads records from r with ReadFinderCSV, looks them up with
+// FindEmails and writes the rows with their results to w
+func FindEmailsCSV(ctx context.Context, r io.Reader, w io.Writer, opts BulkFinderOptions) error {
+	records, header, err := ReadFinderCSV(r)
+	if err != nil {
+		return err
+	}
+	return WriteFinderCSV(w, header, FindEmails(ctx, records, opts))
+}
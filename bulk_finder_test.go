@@ -0,0 +1,91 @@
+package emailverifygo
+
+import (
+	"context"
+	"encoding/csv"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBulkFinder(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	SetApiKey("test_api_key")
+
+	httpmock.RegisterResponder("GET", `=~^(.*)`+ENDPOINT_EMAIL_FINDER+`(.*)\z`,
+		func(r *http.Request) (*http.Response, error) {
+			args := r.URL.Query()
+			switch {
+			case args.Get("domain") == "down.com":
+				return httpmock.NewStringResponse(500, MOCK_ERROR_RESPONSE), nil
+			case args.Get("name") == "John Doe" && args.Get("domain") == "example.com":
+				return httpmock.NewStringResponse(200, MOCK_FINDER_RESPONSE), nil
+			default:
+				return httpmock.NewStringResponse(200, MOCK_FINDER_NOT_FOUND_RESPONSE), nil
+			}
+		},
+	)
+
+	ctx := context.Background()
+
+	t.Run("TestFindEmails", func(t *testing.T) {
+		httpmock.ZeroCallCounters()
+		records := []FinderRecord{
+			{FullName: "John Doe", Domain: "example.com"},
+			{FirstName: "John", LastName: "Doe", CompanyURL: "https://www.Example.com/about"},
+			{FirstName: "Jane", LastName: "Roe", Domain: "example.com"},
+			{FullName: "Nobody"},
+			{FullName: "Some One", Domain: "down.com"},
+		}
+
+		var reported atomic.Int32
+		results := FindEmails(ctx, records, BulkFinderOptions{
+			Concurrency:     3,
+			RequestInterval: time.Millisecond,
+			OnResult:        func(index int, result FinderResult) { reported.Add(1) },
+		})
+
+		assert.Len(t, results, 5, "Expected one result per record")
+		assert.Equal(t, "john.doe@example.com", results[0].Response.Email)
+		assert.True(t, results[1].Response.IsFound(), "Expected the company URL to be reduced to its domain")
+		assert.False(t, results[2].Response.IsFound())
+		assert.ErrorContains(t, results[3].Err, "no domain", "Expected a record without a domain to fail")
+		assert.NotNil(t, results[4].Err, "Expected API errors to be kept per record")
+		assert.Nil(t, results[4].Response)
+		assert.Equal(t, int32(5), reported.Load(), "Expected every result to be reported")
+		assert.Equal(t, 4, httpmock.GetTotalCallCount(), "Expected no API call for an incomplete record")
+	})
+
+	t.Run("TestFindEmailsCSV", func(t *testing.T) {
+		input := "First Name,Last Name,Website,Notes\n" +
+			"John,Doe,example.com,vip\n" +
+			"Jane,Roe,https://example.com,\n" +
+			"Some,One,down.com,retry\n"
+
+		var output strings.Builder
+		err := FindEmailsCSV(ctx, strings.NewReader(input), &output, BulkFinderOptions{RequestInterval: time.Millisecond})
+		assert.Nil(t, err, "Expected no error")
+
+		rows, err := csv.NewReader(strings.NewReader(output.String())).ReadAll()
+		assert.Nil(t, err, "Expected valid CSV output")
+		if assert.Len(t, rows, 4) {
+			assert.Equal(t, []string{"First Name", "Last Name", "Website", "Notes", "email", "status", "error"}, rows[0])
+			assert.Equal(t, []string{"John", "Doe", "example.com", "vip", "john.doe@example.com", "found", ""}, rows[1])
+			assert.Equal(t, []string{"Jane", "Roe", "https://example.com", "", "", "not_found", ""}, rows[2])
+			assert.Equal(t, FINDER_STATUS_ERROR, rows[3][5])
+			assert.NotContains(t, rows[3][6], "test_api_key", "Expected the API key to be redacted from errors")
+		}
+	})
+
+	t.Run("TestReadFinderCSVMissingColumns", func(t *testing.T) {
+		_, _, err := ReadFinderCSV(strings.NewReader("name,phone\nJohn Doe,555\n"))
+		assert.ErrorContains(t, err, "domain", "Expected a missing domain column to be reported")
+	})
+}
@@ -0,0 +1,120 @@
+package emailverifygo
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// ValidationCache stores single email validation results so repeated checks of
+// the same address don't spend another credit. Keys are normalized addresses.
+type ValidationCache interface {
+	Get(email string) (*ValidateResponse, bool)
+	Set(email string, response *ValidateResponse)
+}
+
+type cacheEntry struct {
+	response  ValidateResponse
+	expiresAt time.Time
+}
+
+// MemoryValidationCache is an in-memory ValidationCache with a fixed time to live
+type MemoryValidationCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]cacheEntry
+}
+
+// NewMemoryValidationCache creates a cache whose entries expire after ttl.
+// A ttl of zero keeps entries until the process exits.
+func NewMemoryValidationCache(ttl time.Duration) *MemoryValidationCache {
+	return &MemoryValidationCache{ttl: ttl, entries: make(map[string]cacheEntry)}
+}
+
+// Get returns a copy of the cached result for the address, if it has not expired
+func (c *MemoryValidationCache) Get(email string) (*ValidateResponse, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := NormalizeEmail(email)
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if !entry.expiresAt.IsZero() && time.Now().After(entry.expiresAt) {
+		delete(c.entries, key)
+		return nil, false
+	}
+	response := entry.response
+	return &response, true
+}
+
+// Set stores a copy of the result for the address
+func (c *MemoryValidationCache) Set(email string, response *ValidateResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry := cacheEntry{response: *response}
+	if c.ttl > 0 {
+		entry.expiresAt = time.Now().Add(c.ttl)
+	}
+	c.entries[NormalizeEmail(email)] = entry
+}
+
+// Sweep evicts every expired entry and returns how many were removed. Expired
+// entries are otherwise only evicted when their address is read again, so
+// long-running processes should call it periodically.
+func (c *MemoryValidationCache) Sweep() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	removed := 0
+	for key, entry := range c.entries {
+		if !entry.expiresAt.IsZero() && now.After(entry.expiresAt) {
+			delete(c.entries, key)
+			removed++
+		}
+	}
+	return removed
+}
+
+// Len returns the number of entries, including expired ones not yet evicted
+func (c *MemoryValidationCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
+
+// ValidateWithCache validates an email address, answering from the cache when it can.
+// Successful API results are stored in the cache. A nil cache behaves like Validate.
+//
+// Parameters:
+//   - cache: The cache to read from and write to
+//   - email: The email address to validate
+//
+// Returns:
+//   - *ValidateResponse: The validation result
+//   - error: Any error that occurred during validation
+func ValidateWithCache(cache ValidationCache, email string) (*ValidateResponse, error) {
+	return ValidateWithCacheContext(context.Background(), cache, email)
+}
+
+// ValidateWithCacheContext is like ValidateWithCache but carries ctx to the API call on a cache miss
+func ValidateWithCacheContext(ctx context.Context, cache ValidationCache, email string) (*ValidateResponse, error) {
+	if cache == nil {
+		return ValidateContext(ctx, email)
+	}
+	response, ok := cache.Get(email)
+	observeCache(ok)
+	if ok {
+		return response, nil
+	}
+
+	response, err := ValidateContext(ctx, email)
+	if err != nil {
+		return response, err
+	}
+	cache.Set(email, response)
+	return response, nil
+}
@@ -0,0 +1,29 @@
+package emailverifygo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryValidationCache(t *testing.T) {
+	t.Run("TestSweep", func(t *testing.T) {
+		cache := NewMemoryValidationCache(50 * time.Millisecond)
+		cache.Set("one@example.com", &ValidateResponse{Email: "one@example.com", Status: STATUS_VALID})
+		cache.Set("two@example.com", &ValidateResponse{Email: "two@example.com", Status: STATUS_VALID})
+		time.Sleep(60 * time.Millisecond)
+		cache.Set("three@example.com", &ValidateResponse{Email: "three@example.com", Status: STATUS_VALID})
+
+		assert.Equal(t, 2, cache.Sweep(), "Expected the expired entries to be removed")
+		assert.Equal(t, 1, cache.Len())
+	})
+
+	t.Run("TestSweepWithoutTTL", func(t *testing.T) {
+		cache := NewMemoryValidationCache(0)
+		cache.Set("one@example.com", &ValidateResponse{Email: "one@example.com", Status: STATUS_VALID})
+
+		assert.Equal(t, 0, cache.Sweep(), "Expected entries without a time to live to be kept")
+		assert.Equal(t, 1, cache.Len())
+	})
+}
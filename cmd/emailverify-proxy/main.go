@@ -0,0 +1,91 @@
+// Command emailverify-proxy serves the EmailVerify API to internal services
+// without handing them the real API key.
+//
+// The upstream key is read from EMAIL_VERIFY_API_KEY (or a .env file) and
+// injected server-side. Internal services call the proxy with their own proxy
+// token as the `key` parameter or X-Api-Key header, so pointing them at it only takes:
+//
+//	emailverifygo.SetURI("http://emailverify-proxy:8080")
+//	emailverifygo.SetApiKey("<PROXY_TOKEN>")
+//
+// Callers are listed in a JSON file:
+//
+//	[{"name": "signup", "key": "<PROXY_TOKEN>", "daily_quota": 1000, "requests_per_second": 5}]
+//
+// Single validations are cached, with expired results swept out every
+// -cache-sweep. Each caller is rate limited and held to its daily credit
+// quota, and usage is reported at /usage. Callers can only read
+// the results of batch tasks they submitted; pass -tasks to keep that record
+// across restarts, otherwise tasks submitted before a restart are refused.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/Clustox/emailverifygo"
+)
+
+func main() {
+	listen := flag.String("listen", ":8080", "address to listen on")
+	callersPath := flag.String("callers", "callers.json", "JSON file listing the callers allowed to use the proxy")
+	cacheTTL := flag.Duration("cache-ttl", 24*time.Hour, "how long single validation results are cached, 0 disables the cache")
+	adminKey := flag.String("admin-key", os.Getenv("EMAIL_VERIFY_PROXY_ADMIN_KEY"), "key allowed to read every caller's usage")
+	keyHeader := flag.String("key-header", DEFAULT_KEY_HEADER, "header callers may send their proxy token in")
+	cacheSweep := flag.Duration("cache-sweep", 10*time.Minute, "how often expired validation results are evicted from the cache")
+	tasksPath := flag.String("tasks", "", "JSON file recording which caller submitted each batch task, kept in memory if empty")
+	flag.Parse()
+
+	emailverifygo.LoadEnvFromFile()
+	if emailverifygo.API_KEY == "" {
+		log.Fatal(emailverifygo.ErrMissingAPIKey)
+	}
+
+	callers, err := loadCallers(*callersPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var cache emailverifygo.ValidationCache
+	if *cacheTTL > 0 {
+		memory := emailverifygo.NewMemoryValidationCache(*cacheTTL)
+		go sweepCache(memory, *cacheSweep)
+		cache = memory
+	}
+
+	server := newProxy(callers, cache, *adminKey)
+	server.keyHeader = *keyHeader
+	if *tasksPath != "" {
+		server.jobs = emailverifygo.NewFileBatchJobStore(*tasksPath)
+	}
+	log.Printf("emailverify-proxy listening on %s for %d callers", *listen, len(callers))
+	log.Fatal(http.ListenAndServe(*listen, server.routes()))
+}
+
+// sweepCache evicts expired results every interval, so addresses that are
+// never looked up again do not keep the cache growing
+func sweepCache(cache *emailverifygo.MemoryValidationCache, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	for range time.Tick(interval) {
+		cache.Sweep()
+	}
+}
+
+func loadCallers(path string) ([]Caller, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var callers []Caller
+	if err := json.Unmarshal(data, &callers); err != nil {
+		return nil, err
+	}
+	return callers, nil
+}
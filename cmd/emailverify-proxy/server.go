@@ -0,0 +1,389 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Clustox/emailverifygo"
+)
+
+// DEFAULT_KEY_HEADER is the header callers may send their proxy token in
+const DEFAULT_KEY_HEADER = "X-Api-Key"
+
+// Caller is an internal service allowed to use the proxy. Its Key is a proxy
+// token, never the EmailVerify API key.
+type Caller struct {
+	Name              string  `json:"name"`
+	Key               string  `json:"key"`
+	DailyQuota        int     `json:"daily_quota"`         // Credits per UTC day, 0 for unlimited
+	RequestsPerSecond float64 `json:"requests_per_second"` // Sustained request rate, 0 for unlimited
+}
+
+// Usage is what one caller has consumed through the proxy
+type Usage struct {
+	Caller       string `json:"caller"`
+	Requests     int    `json:"requests"`
+	CacheHits    int    `json:"cache_hits"`
+	Credits      int    `json:"credits"`       // Credits spent since the process started
+	CreditsToday int    `json:"credits_today"` // Credits counted against today's quota
+	Throttled    int    `json:"throttled"`
+	Errors       int    `json:"errors"`
+}
+
+type callerState struct {
+	Caller
+
+	tokens   float64
+	refilled time.Time
+	day      string
+	usage    Usage
+}
+
+// allow takes one token from the caller's bucket
+func (c *callerState) allow(now time.Time) bool {
+	if c.RequestsPerSecond <= 0 {
+		return true
+	}
+
+	burst := c.RequestsPerSecond
+	if burst < 1 {
+		burst = 1
+	}
+	if c.refilled.IsZero() {
+		c.tokens = burst
+	} else {
+		c.tokens += now.Sub(c.refilled).Seconds() * c.RequestsPerSecond
+		if c.tokens > burst {
+			c.tokens = burst
+		}
+	}
+	c.refilled = now
+
+	if c.tokens < 1 {
+		return false
+	}
+	c.tokens--
+	return true
+}
+
+// reserve counts credits against today's quota, refusing if it would be exceeded
+func (c *callerState) reserve(now time.Time, credits int) bool {
+	day := now.UTC().Format("2006-01-02")
+	if day != c.day {
+		c.day = day
+		c.usage.CreditsToday = 0
+	}
+	if c.DailyQuota > 0 && c.usage.CreditsToday+credits > c.DailyQuota {
+		return false
+	}
+	c.usage.CreditsToday += credits
+	c.usage.Credits += credits
+	return true
+}
+
+// refund gives back credits reserved for a call that did not spend them
+func (c *callerState) refund(credits int) {
+	c.usage.CreditsToday -= credits
+	c.usage.Credits -= credits
+}
+
+// proxy serves the EmailVerify endpoints to internal callers and injects the real
+// API key server-side
+type proxy struct {
+	mu        sync.Mutex
+	callers   map[string]*callerState
+	jobs      emailverifygo.BatchJobStore // Batch tasks, with the name of the caller that submitted each
+	cache     emailverifygo.ValidationCache
+	adminKey  string
+	keyHeader string // Header callers may send their token in instead of the `key` parameter
+	logger    *log.Logger
+	now       func() time.Time
+}
+
+func newProxy(callers []Caller, cache emailverifygo.ValidationCache, adminKey string) *proxy {
+	p := &proxy{
+		callers:   make(map[string]*callerState),
+		jobs:      emailverifygo.NewMemoryBatchJobStore(),
+		cache:     cache,
+		adminKey:  adminKey,
+		keyHeader: DEFAULT_KEY_HEADER,
+		logger:    log.New(os.Stderr, "emailverify-proxy ", log.LstdFlags),
+		now:       time.Now,
+	}
+	for _, caller := range callers {
+		p.callers[caller.Key] = &callerState{Caller: caller, usage: Usage{Caller: caller.Name}}
+	}
+	return p
+}
+
+func (p *proxy) routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(emailverifygo.ENDPOINT_VALIDATE, p.handleValidate)
+	mux.HandleFunc(emailverifygo.ENDPOINT_VALIDATE_BATCH, p.handleValidateBatch)
+	mux.HandleFunc(emailverifygo.ENDPOINT_BATCH_RESULT, p.handleBatchResult)
+	mux.HandleFunc(emailverifygo.ENDPOINT_EMAIL_FINDER, p.handleFinder)
+	mux.HandleFunc(emailverifygo.ENDPOINT_ACCOUNT_BALANCE, p.handleAccountBalance)
+	mux.HandleFunc("/usage", p.handleUsage)
+	return mux
+}
+
+func (p *proxy) handleValidate(w http.ResponseWriter, r *http.Request) {
+	caller, ok := p.admit(w, r, r.URL.Query().Get("key"))
+	if !ok {
+		return
+	}
+
+	email := r.URL.Query().Get("email")
+	if email == "" {
+		writeError(w, http.StatusBadRequest, "Missing parameter: email.")
+		return
+	}
+
+	if p.cache != nil {
+		if response, hit := p.cache.Get(email); hit {
+			p.mu.Lock()
+			caller.usage.CacheHits++
+			p.mu.Unlock()
+			writeJSON(w, response)
+			return
+		}
+	}
+
+	if !p.reserve(w, caller, 1) {
+		return
+	}
+	response, err := emailverifygo.ValidateContext(r.Context(), email)
+	if err != nil {
+		p.upstreamError(w, caller, 1, err)
+		return
+	}
+	if p.cache != nil {
+		p.cache.Set(email, response)
+	}
+	writeJSON(w, response)
+}
+
+func (p *proxy) handleValidateBatch(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed.")
+		return
+	}
+
+	var request emailverifygo.BatchValidateRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body.")
+		return
+	}
+	caller, ok := p.admit(w, r, request.Key)
+	if !ok {
+		return
+	}
+
+	emails := make([]string, len(request.EmailBatch))
+	for i, address := range request.EmailBatch {
+		emails[i] = address.Address
+	}
+	if !p.reserve(w, caller, len(emails)) {
+		return
+	}
+
+	response, err := emailverifygo.ValidateBatchContext(r.Context(), request.Title, emails)
+	if err != nil {
+		p.upstreamError(w, caller, len(emails), err)
+		return
+	}
+	// Duplicates and rejected addresses are not charged, so give their quota back
+	if unspent := len(emails) - response.CreditsCharged(); unspent > 0 {
+		p.mu.Lock()
+		caller.refund(unspent)
+		p.mu.Unlock()
+	}
+
+	now := p.now()
+	job := &emailverifygo.BatchJob{
+		TaskID:      response.TaskID,
+		Title:       request.Title,
+		Emails:      emails,
+		SubmittedAt: now,
+		Status:      emailverifygo.BATCH_STATUS_SUBMITTED,
+		UpdatedAt:   now,
+		Owner:       caller.Name,
+	}
+	if err := p.jobs.Save(job); err != nil {
+		// The caller could never read the results back, so tell them rather than hand out the ID
+		p.logger.Printf("batch %d for %s submitted but not saved: %v", response.TaskID, caller.Name, err)
+		writeError(w, http.StatusInternalServerError, "Batch submitted but not recorded.")
+		return
+	}
+	writeJSON(w, response)
+}
+
+func (p *proxy) handleBatchResult(w http.ResponseWriter, r *http.Request) {
+	caller, ok := p.admit(w, r, r.URL.Query().Get("key"))
+	if !ok {
+		return
+	}
+
+	taskID, err := strconv.Atoi(r.URL.Query().Get("task_id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Missing parameter: task_id.")
+		return
+	}
+
+	// Tasks the proxy has no record of are refused, so one caller cannot read
+	// another's results by guessing IDs through the shared upstream key
+	job, err := p.jobs.Get(taskID)
+	if err != nil && !errors.Is(err, emailverifygo.ErrBatchJobNotFound) {
+		p.logger.Printf("reading batch %d: %v", taskID, err)
+		writeError(w, http.StatusInternalServerError, "Task store unavailable.")
+		return
+	}
+	if job == nil || job.Owner != caller.Name {
+		writeError(w, http.StatusNotFound, "Task not found.")
+		return
+	}
+
+	response, err := emailverifygo.GetBatchResultsContext(r.Context(), taskID)
+	if err != nil {
+		p.upstreamError(w, caller, 0, err)
+		return
+	}
+	writeJSON(w, response)
+}
+
+func (p *proxy) handleFinder(w http.ResponseWriter, r *http.Request) {
+	caller, ok := p.admit(w, r, r.URL.Query().Get("key"))
+	if !ok {
+		return
+	}
+	domain, err := emailverifygo.ExtractDomain(r.URL.Query().Get("domain"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid parameter: domain.")
+		return
+	}
+	if !p.reserve(w, caller, 1) {
+		return
+	}
+
+	response, err := emailverifygo.FindEmailContext(r.Context(), r.URL.Query().Get("name"), domain)
+	if err != nil {
+		p.upstreamError(w, caller, 1, err)
+		return
+	}
+	writeJSON(w, response)
+}
+
+func (p *proxy) handleAccountBalance(w http.ResponseWriter, r *http.Request) {
+	caller, ok := p.admit(w, r, r.URL.Query().Get("key"))
+	if !ok {
+		return
+	}
+
+	response, err := emailverifygo.GetAccountBalanceContext(r.Context())
+	if err != nil {
+		p.upstreamError(w, caller, 0, err)
+		return
+	}
+	writeJSON(w, response)
+}
+
+// handleUsage reports the caller's own usage, or every caller's usage for the admin key
+func (p *proxy) handleUsage(w http.ResponseWriter, r *http.Request) {
+	key := r.URL.Query().Get("key")
+	if key == "" && p.keyHeader != "" {
+		key = r.Header.Get(p.keyHeader)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.adminKey != "" && key == p.adminKey {
+		all := make([]Usage, 0, len(p.callers))
+		for _, caller := range p.callers {
+			all = append(all, caller.usage)
+		}
+		sort.Slice(all, func(i, j int) bool { return all[i].Caller < all[j].Caller })
+		writeJSON(w, all)
+		return
+	}
+
+	caller, ok := p.callers[key]
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "Invalid API key.")
+		return
+	}
+	writeJSON(w, caller.usage)
+}
+
+// admit identifies the caller and applies its rate limit
+func (p *proxy) admit(w http.ResponseWriter, r *http.Request, key string) (*callerState, bool) {
+	if key == "" && p.keyHeader != "" {
+		key = r.Header.Get(p.keyHeader)
+	}
+	if key == "" {
+		writeError(w, http.StatusBadRequest, "Missing parameter: key.")
+		return nil, false
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	caller, ok := p.callers[key]
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "Invalid API key.")
+		return nil, false
+	}
+	caller.usage.Requests++
+	if !caller.allow(p.now()) {
+		caller.usage.Throttled++
+		writeError(w, http.StatusTooManyRequests, "Rate limit exceeded.")
+		return nil, false
+	}
+	return caller, true
+}
+
+func (p *proxy) reserve(w http.ResponseWriter, caller *callerState, credits int) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !caller.reserve(p.now(), credits) {
+		caller.usage.Throttled++
+		writeError(w, http.StatusTooManyRequests, "Daily quota exceeded.")
+		return false
+	}
+	return true
+}
+
+// upstreamError refunds the reserved credits and answers without leaking upstream details
+func (p *proxy) upstreamError(w http.ResponseWriter, caller *callerState, credits int, err error) {
+	p.mu.Lock()
+	caller.refund(credits)
+	caller.usage.Errors++
+	p.mu.Unlock()
+
+	p.logger.Printf("caller %s: upstream request failed: %s", caller.Name, emailverifygo.RedactAPIKey(err.Error()))
+	if errors.Is(err, emailverifygo.ErrMissingAPIKey) {
+		writeError(w, http.StatusInternalServerError, "Proxy is not configured.")
+		return
+	}
+	writeError(w, http.StatusBadGateway, fmt.Sprintf("Upstream request failed for %s.", caller.Name))
+}
+
+func writeJSON(w http.ResponseWriter, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(value)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
@@ -0,0 +1,209 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Clustox/emailverifygo"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+const upstreamKey = "upstream_secret_key"
+
+func TestProxy(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	emailverifygo.SetApiKey(upstreamKey)
+	emailverifygo.SetURI("https://app.emailverify.io")
+
+	// The upstream only accepts the real key, and like a real transport gives up on cancelled requests
+	upstream := func(body string) httpmock.Responder {
+		return func(r *http.Request) (*http.Response, error) {
+			if err := r.Context().Err(); err != nil {
+				return nil, err
+			}
+			if r.URL.Query().Get("key") != upstreamKey {
+				return httpmock.NewStringResponse(401, emailverifygo.MOCK_ERROR_RESPONSE), nil
+			}
+			return httpmock.NewStringResponse(200, body), nil
+		}
+	}
+	httpmock.RegisterResponder("GET", `=~^(.*)`+emailverifygo.ENDPOINT_VALIDATE+`(.*)\z`, upstream(emailverifygo.MOCK_VALID_RESPONSE))
+	httpmock.RegisterResponder("GET", `=~^(.*)`+emailverifygo.ENDPOINT_EMAIL_FINDER+`(.*)\z`, upstream(emailverifygo.MOCK_FINDER_RESPONSE))
+	httpmock.RegisterResponder("GET", `=~^(.*)`+emailverifygo.ENDPOINT_BATCH_RESULT+`(.*)\z`, upstream(emailverifygo.MOCK_BATCH_RESULTS_RESPONSE))
+	httpmock.RegisterResponder("GET", `=~^(.*)`+emailverifygo.ENDPOINT_ACCOUNT_BALANCE+`(.*)\z`,
+		httpmock.NewStringResponder(500, `{"error": "upstream exploded"}`))
+	httpmock.RegisterResponder("POST", `=~^(.*)`+emailverifygo.ENDPOINT_VALIDATE_BATCH+`(.*)\z`,
+		func(r *http.Request) (*http.Response, error) {
+			var request emailverifygo.BatchValidateRequest
+			json.NewDecoder(r.Body).Decode(&request)
+			if request.Key != upstreamKey {
+				return httpmock.NewStringResponse(401, emailverifygo.MOCK_ERROR_RESPONSE), nil
+			}
+			// Duplicates are removed before charging
+			unique := make(map[string]bool)
+			for _, address := range request.EmailBatch {
+				unique[address.Address] = true
+			}
+			return httpmock.NewStringResponse(200, fmt.Sprintf(`{"status": "success", "task_id": 12345, "count_submitted": %d,
+				"count_duplicates_removed": %d, "count_rejected_emails": 0, "count_processing": %d}`,
+				len(request.EmailBatch), len(request.EmailBatch)-len(unique), len(unique))), nil
+		},
+	)
+
+	p := newProxy([]Caller{
+		{Name: "signup", Key: "signup-token", DailyQuota: 3},
+		{Name: "crm", Key: "crm-token", RequestsPerSecond: 1},
+		{Name: "ops", Key: "ops-token"},
+	}, emailverifygo.NewMemoryValidationCache(time.Hour), "admin-token")
+	p.logger = log.New(io.Discard, "", 0)
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	p.now = func() time.Time { return now }
+
+	// Serve requests straight through the proxy router
+	get := func(path string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		p.routes().ServeHTTP(recorder, httptest.NewRequest("GET", path, nil))
+		return recorder
+	}
+
+	t.Run("TestValidateInjectsKeyAndCaches", func(t *testing.T) {
+		httpmock.ZeroCallCounters()
+		for i := 0; i < 2; i++ {
+			recorder := get(emailverifygo.ENDPOINT_VALIDATE + "?key=signup-token&email=valid@example.com")
+			assert.Equal(t, 200, recorder.Code, "Expected proxied validation to succeed")
+			assert.Contains(t, recorder.Body.String(), `"status":"valid"`, "Expected the upstream result")
+		}
+		assert.Equal(t, 1, httpmock.GetTotalCallCount(), "Expected the second call to be cached")
+	})
+
+	t.Run("TestUnknownCaller", func(t *testing.T) {
+		recorder := get(emailverifygo.ENDPOINT_VALIDATE + "?key=" + upstreamKey + "&email=valid@example.com")
+		assert.Equal(t, 401, recorder.Code, "Expected the upstream key itself to be refused")
+	})
+
+	t.Run("TestDailyQuota", func(t *testing.T) {
+		assert.Equal(t, 200, get(emailverifygo.ENDPOINT_EMAIL_FINDER+"?key=signup-token&name=John+Doe&domain=example.com").Code)
+		assert.Equal(t, 200, get(emailverifygo.ENDPOINT_EMAIL_FINDER+"?key=signup-token&name=John+Doe&domain=example.com").Code)
+		recorder := get(emailverifygo.ENDPOINT_EMAIL_FINDER + "?key=signup-token&name=John+Doe&domain=example.com")
+		assert.Equal(t, 429, recorder.Code, "Expected the fourth credit of the day to be refused")
+
+		now = now.Add(24 * time.Hour)
+		assert.Equal(t, 200, get(emailverifygo.ENDPOINT_EMAIL_FINDER+"?key=signup-token&name=John+Doe&domain=example.com").Code,
+			"Expected the quota to reset the next day")
+		assert.Equal(t, 400, get(emailverifygo.ENDPOINT_EMAIL_FINDER+"?key=signup-token&name=John+Doe&domain=Acme+Inc").Code,
+			"Expected an implausible domain to be refused without spending quota")
+		assert.Equal(t, 400, get(emailverifygo.ENDPOINT_EMAIL_FINDER+"?key=signup-token&name=John+Doe&domain=edu.sg").Code,
+			"Expected a public suffix to be refused without spending quota")
+	})
+
+	t.Run("TestRateLimit", func(t *testing.T) {
+		assert.Equal(t, 404, get(emailverifygo.ENDPOINT_BATCH_RESULT+"?key=crm-token&task_id=12345").Code,
+			"Expected a task the proxy has no record of to be refused")
+		assert.Equal(t, 429, get(emailverifygo.ENDPOINT_BATCH_RESULT+"?key=crm-token&task_id=12345").Code,
+			"Expected a second request within the same second to be throttled")
+		now = now.Add(time.Second)
+	})
+
+	t.Run("TestBatchOwnership", func(t *testing.T) {
+		body := `{"title": "Leads", "key": "crm-token", "email_batch": [{"address": "valid@example.com"}]}`
+		recorder := httptest.NewRecorder()
+		p.routes().ServeHTTP(recorder, httptest.NewRequest("POST", emailverifygo.ENDPOINT_VALIDATE_BATCH, strings.NewReader(body)))
+		assert.Equal(t, 200, recorder.Code, "Expected proxied batch to succeed")
+
+		recorder = get(emailverifygo.ENDPOINT_BATCH_RESULT + "?key=signup-token&task_id=12345")
+		assert.Equal(t, 404, recorder.Code, "Expected another caller's task to be hidden")
+
+		now = now.Add(time.Second)
+		assert.Equal(t, 200, get(emailverifygo.ENDPOINT_BATCH_RESULT+"?key=crm-token&task_id=12345").Code,
+			"Expected the owner to read its own task")
+		now = now.Add(time.Second)
+	})
+
+	t.Run("TestBatchQuotaRefunded", func(t *testing.T) {
+		body := `{"title": "Dupes", "key": "ops-token", "email_batch": [{"address": "a@example.com"}, {"address": "a@example.com"}, {"address": "b@example.com"}]}`
+		recorder := httptest.NewRecorder()
+		p.routes().ServeHTTP(recorder, httptest.NewRequest("POST", emailverifygo.ENDPOINT_VALIDATE_BATCH, strings.NewReader(body)))
+		assert.Equal(t, 200, recorder.Code, "Expected proxied batch to succeed")
+
+		var usage Usage
+		json.Unmarshal(get("/usage?key=ops-token").Body.Bytes(), &usage)
+		assert.Equal(t, 2, usage.CreditsToday, "Expected the duplicate's credit to be given back")
+		assert.Equal(t, 2, usage.Credits)
+	})
+
+	t.Run("TestBatchOwnershipSurvivesRestart", func(t *testing.T) {
+		jobs := emailverifygo.NewFileBatchJobStore(t.TempDir() + "/tasks.json")
+		start := func() *proxy {
+			restarted := newProxy([]Caller{{Name: "crm", Key: "crm-token"}, {Name: "signup", Key: "signup-token"}}, nil, "")
+			restarted.logger = log.New(io.Discard, "", 0)
+			restarted.jobs = jobs
+			return restarted
+		}
+		serve := func(p *proxy, r *http.Request) int {
+			recorder := httptest.NewRecorder()
+			p.routes().ServeHTTP(recorder, r)
+			return recorder.Code
+		}
+
+		body := `{"title": "Leads", "key": "crm-token", "email_batch": [{"address": "valid@example.com"}]}`
+		assert.Equal(t, 200, serve(start(), httptest.NewRequest("POST", emailverifygo.ENDPOINT_VALIDATE_BATCH, strings.NewReader(body))))
+
+		restarted := start()
+		assert.Equal(t, 200, serve(restarted, httptest.NewRequest("GET", emailverifygo.ENDPOINT_BATCH_RESULT+"?key=crm-token&task_id=12345", nil)),
+			"Expected the owner to keep access after a restart")
+		assert.Equal(t, 404, serve(restarted, httptest.NewRequest("GET", emailverifygo.ENDPOINT_BATCH_RESULT+"?key=signup-token&task_id=12345", nil)))
+	})
+
+	t.Run("TestUpstreamErrorDoesNotLeakKey", func(t *testing.T) {
+		recorder := get(emailverifygo.ENDPOINT_ACCOUNT_BALANCE + "?key=signup-token")
+		assert.Equal(t, 502, recorder.Code, "Expected upstream failure to map to bad gateway")
+		assert.NotContains(t, recorder.Body.String(), upstreamKey, "Expected the upstream key to stay hidden")
+	})
+
+	t.Run("TestCancelledRequestStopsUpstream", func(t *testing.T) {
+		// A shared request outlives its callers, so validate without coalescing to see the cancellation reach upstream
+		emailverifygo.SetCoalescing(false)
+		defer emailverifygo.SetCoalescing(true)
+		var before Usage
+		json.Unmarshal(get("/usage?key=ops-token").Body.Bytes(), &before)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		for _, path := range []string{
+			emailverifygo.ENDPOINT_VALIDATE + "?key=ops-token&email=other@example.com",
+			emailverifygo.ENDPOINT_EMAIL_FINDER + "?key=ops-token&name=John+Doe&domain=example.com",
+		} {
+			recorder := httptest.NewRecorder()
+			p.routes().ServeHTTP(recorder, httptest.NewRequest("GET", path, nil).WithContext(ctx))
+			assert.Equal(t, 502, recorder.Code, "Expected the client's cancellation to reach the upstream call for %s", path)
+		}
+
+		var usage Usage
+		json.Unmarshal(get("/usage?key=ops-token").Body.Bytes(), &usage)
+		assert.Equal(t, before.Credits, usage.Credits, "Expected the reserved credits to be refunded")
+	})
+
+	t.Run("TestUsage", func(t *testing.T) {
+		var usage Usage
+		json.Unmarshal(get("/usage?key=signup-token").Body.Bytes(), &usage)
+		assert.Equal(t, "signup", usage.Caller, "Expected the caller's own usage")
+		assert.Equal(t, 1, usage.CacheHits, "Expected the cached validation to be counted")
+		assert.Equal(t, 4, usage.Credits, "Expected one validation and three finder credits")
+		assert.Equal(t, 1, usage.Errors, "Expected the failed balance call to be counted")
+
+		var all []Usage
+		json.Unmarshal(get("/usage?key=admin-token").Body.Bytes(), &all)
+		assert.Equal(t, 3, len(all), "Expected every caller for the admin key")
+	})
+}
@@ -0,0 +1,118 @@
+package emailverifygo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// coalesceValidations makes concurrent Validate calls for the same address share one request
+var coalesceValidations = true
+
+// SetCoalescing enables or disables sharing one in-flight request between
+// concurrent Validate calls for the same normalized address. It is enabled by default.
+func SetCoalescing(enabled bool) {
+	coalesceValidations = enabled
+}
+
+// DEFAULT_COALESCE_TIMEOUT bounds a shared validation request joined only by callers without a deadline
+const DEFAULT_COALESCE_TIMEOUT = 2 * time.Minute
+
+// inflightValidation is one validation request shared by every caller asking for its address
+type inflightValidation struct {
+	done     chan struct{}
+	response *ValidateResponse
+	err      error
+
+	// Guarded by inflightMu
+	ctx      context.Context
+	cancel   context.CancelFunc
+	timer    *time.Timer
+	waiters  int
+	deadline time.Time
+}
+
+var (
+	inflightMu          sync.Mutex
+	inflightValidations = make(map[string]*inflightValidation)
+)
+
+// coalesceValidate validates email, joining a request already in flight for the
+// same address if there is one. The request runs detached from the caller that
+// started it, so that caller giving up does not fail the others; each caller
+// stops waiting when its own ctx is done. The request is bounded by the latest
+// deadline among the callers waiting for it, DEFAULT_COALESCE_TIMEOUT for
+// callers without one, and is cancelled once every caller has stopped waiting.
+// It reports whether the result was shared with a request started by another caller.
+func coalesceValidate(ctx context.Context, email string) (*ValidateResponse, bool, error) {
+	key := NormalizeEmail(email)
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(DEFAULT_COALESCE_TIMEOUT)
+	}
+
+	inflightMu.Lock()
+	call, shared := inflightValidations[key]
+	if shared && call.ctx.Err() != nil {
+		// The request timed out or was abandoned and is only winding down, so start another
+		shared = false
+	}
+	if !shared {
+		requestCtx, cancel := context.WithCancelCause(context.WithoutCancel(ctx))
+		call = &inflightValidation{done: make(chan struct{}), ctx: requestCtx, cancel: func() { cancel(context.Canceled) }, deadline: deadline}
+		call.timer = time.AfterFunc(time.Until(deadline), func() {
+			cancel(context.DeadlineExceeded)
+		})
+		inflightValidations[key] = call
+		go func() {
+			call.response, call.err = validateEmail(requestCtx, email)
+			call.timer.Stop()
+			if call.err != nil && errors.Is(context.Cause(requestCtx), context.DeadlineExceeded) {
+				// The HTTP client only sees a cancellation, but the request ran out of time
+				call.err = fmt.Errorf("%w: %w", context.DeadlineExceeded, call.err)
+			}
+			cancel(context.Canceled)
+
+			inflightMu.Lock()
+			if inflightValidations[key] == call {
+				delete(inflightValidations, key)
+			}
+			inflightMu.Unlock()
+			close(call.done)
+		}()
+	} else if deadline.After(call.deadline) {
+		call.deadline = deadline
+		call.timer.Reset(time.Until(deadline))
+	}
+	call.waiters++
+	inflightMu.Unlock()
+
+	select {
+	case <-ctx.Done():
+		inflightMu.Lock()
+		call.waiters--
+		if call.waiters == 0 {
+			// Nobody is waiting any more, so stop the request and let later callers start afresh
+			call.cancel()
+			if inflightValidations[key] == call {
+				delete(inflightValidations, key)
+			}
+		}
+		inflightMu.Unlock()
+		return nil, shared, ctx.Err()
+	case <-call.done:
+		if err := ctx.Err(); err != nil {
+			// The request may have ended with this caller's deadline; report the caller's own error
+			return nil, shared, err
+		}
+	}
+
+	if call.response == nil {
+		return nil, shared, call.err
+	}
+	// Every caller gets its own copy to modify
+	response := *call.response
+	return &response, shared, call.err
+}
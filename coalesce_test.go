@@ -0,0 +1,212 @@
+package emailverifygo
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCoalescing(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	SetApiKey("test_api_key")
+
+	release := make(chan struct{})
+	httpmock.RegisterResponder("GET", `=~^(.*)`+ENDPOINT_VALIDATE+`(.*)\z`,
+		func(r *http.Request) (*http.Response, error) {
+			<-release
+			return httpmock.NewStringResponse(200, MOCK_VALID_RESPONSE), nil
+		},
+	)
+
+	// validateConcurrently starts n callers and releases the API once they are all waiting,
+	// adding the credits they report to spent
+	var spent atomic.Int32
+	validateConcurrently := func(n int, ctx context.Context) []error {
+		errs := make([]error, n)
+		var callers sync.WaitGroup
+		for i := 0; i < n; i++ {
+			callers.Add(1)
+			go func(i int) {
+				defer callers.Done()
+				email := "valid@example.com"
+				if i%2 == 1 {
+					email = " Valid@Example.com"
+				}
+				response, credits, err := validateContext(ctx, email)
+				spent.Add(int32(credits))
+				if err == nil && response.Status != STATUS_VALID {
+					t.Errorf("Expected a valid result, got %q", response.Status)
+				}
+				errs[i] = err
+			}(i)
+		}
+		time.Sleep(20 * time.Millisecond)
+		release <- struct{}{}
+		callers.Wait()
+		return errs
+	}
+
+	t.Run("TestSharedRequest", func(t *testing.T) {
+		httpmock.ZeroCallCounters()
+		spent.Store(0)
+
+		for _, err := range validateConcurrently(5, context.Background()) {
+			assert.Nil(t, err, "Expected every caller to get the shared result")
+		}
+		assert.Equal(t, 1, httpmock.GetTotalCallCount(), "Expected one request for concurrent duplicate calls")
+		assert.Equal(t, int32(CREDITS_PER_VALIDATION), spent.Load(), "Expected only the caller that made the request to report a credit")
+	})
+
+	t.Run("TestCallerCancellation", func(t *testing.T) {
+		httpmock.ZeroCallCounters()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancelled := make(chan error)
+		go func() {
+			_, err := ValidateContext(ctx, "valid@example.com")
+			cancelled <- err
+		}()
+		time.Sleep(10 * time.Millisecond)
+
+		waiter := make(chan error)
+		go func() {
+			_, err := Validate("valid@example.com")
+			waiter <- err
+		}()
+		time.Sleep(10 * time.Millisecond)
+
+		cancel()
+		assert.ErrorIs(t, <-cancelled, context.Canceled, "Expected the cancelled caller to stop waiting")
+		release <- struct{}{}
+		assert.Nil(t, <-waiter, "Expected the other caller to still get the result")
+		assert.Equal(t, 1, httpmock.GetTotalCallCount())
+	})
+
+	t.Run("TestDisabled", func(t *testing.T) {
+		httpmock.ZeroCallCounters()
+		SetCoalescing(false)
+		defer SetCoalescing(true)
+
+		done := make(chan struct{})
+		go func() {
+			for i := 0; i < 2; i++ {
+				release <- struct{}{}
+			}
+			close(done)
+		}()
+		var callers sync.WaitGroup
+		for i := 0; i < 2; i++ {
+			callers.Add(1)
+			go func() {
+				defer callers.Done()
+				_, err := Validate("valid@example.com")
+				assert.Nil(t, err)
+			}()
+		}
+		callers.Wait()
+		<-done
+		assert.Equal(t, 2, httpmock.GetTotalCallCount(), "Expected one request per call without coalescing")
+	})
+}
+
+func TestCoalescingDeadlines(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	SetApiKey("test_api_key")
+
+	// The upstream answers after delay unless the request is cancelled first.
+	// Requests cancelled before they are answered leave delay for the next one.
+	delay := make(chan time.Duration, 1)
+	cancelled := make(chan struct{}, 1)
+	httpmock.RegisterResponder("GET", `=~^(.*)`+ENDPOINT_VALIDATE+`(.*)\z`,
+		func(r *http.Request) (*http.Response, error) {
+			var wait time.Duration
+			select {
+			case wait = <-delay:
+			case <-r.Context().Done():
+				return nil, r.Context().Err()
+			}
+			select {
+			case <-time.After(wait):
+				return httpmock.NewStringResponse(200, MOCK_VALID_RESPONSE), nil
+			case <-r.Context().Done():
+				cancelled <- struct{}{}
+				return nil, r.Context().Err()
+			}
+		},
+	)
+
+	inflight := func() int {
+		inflightMu.Lock()
+		defer inflightMu.Unlock()
+		return len(inflightValidations)
+	}
+
+	t.Run("TestDeadlineAbortsRequest", func(t *testing.T) {
+		delay <- time.Hour
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		_, err := ValidateContext(ctx, "valid@example.com")
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		select {
+		case <-cancelled:
+		case <-time.After(time.Second):
+			t.Fatal("Expected the hung request to be cancelled")
+		}
+		assert.Eventually(t, func() bool { return inflight() == 0 }, time.Second, 5*time.Millisecond,
+			"Expected the in-flight entry to be dropped")
+	})
+
+	t.Run("TestRequestDeadlineReported", func(t *testing.T) {
+		delay <- time.Hour
+		// The caller's context reports a deadline but is never done, so only the request can time out
+		ctx := deadlineOnlyContext{Context: context.Background(), deadline: time.Now().Add(20 * time.Millisecond)}
+
+		_, err := ValidateContext(ctx, "valid@example.com")
+		assert.ErrorIs(t, err, context.DeadlineExceeded, "Expected the request's timeout to be reported as a deadline")
+		<-cancelled
+	})
+
+	t.Run("TestLaterDeadlineExtendsRequest", func(t *testing.T) {
+		// Wide margins, so timers firing late on a busy machine do not reorder the steps
+		delay <- 300 * time.Millisecond
+		short, cancelShort := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancelShort()
+		long, cancelLong := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancelLong()
+
+		first := make(chan error)
+		go func() {
+			_, err := ValidateContext(short, "valid@example.com")
+			first <- err
+		}()
+		// Join the short caller's request as soon as it is in flight
+		assert.Eventually(t, func() bool { return inflight() == 1 }, time.Second, time.Millisecond)
+
+		response, err := ValidateContext(long, "valid@example.com")
+		if assert.Nil(t, err, "Expected the longer deadline to keep the shared request alive") {
+			assert.Equal(t, STATUS_VALID, response.Status)
+		}
+		assert.ErrorIs(t, <-first, context.DeadlineExceeded, "Expected the short caller to stop at its own deadline")
+	})
+}
+
+// deadlineOnlyContext reports a deadline without ever being done
+type deadlineOnlyContext struct {
+	context.Context
+	deadline time.Time
+}
+
+func (c deadlineOnlyContext) Deadline() (time.Time, bool) {
+	return c.deadline, true
+}
@@ -0,0 +1,213 @@
+package emailverifygo
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Default cache lifetimes used by NewDomainChecker when an option is left at zero
+const (
+	DEFAULT_DNS_CACHE_TTL    = time.Hour        // For domains that accept mail
+	DEFAULT_DNS_NEGATIVE_TTL = 10 * time.Minute // For domains that do not
+)
+
+// Resolver looks up the DNS records DomainChecker needs. *net.Resolver
+// implements it; StaticResolver answers from memory for tests.
+type Resolver interface {
+	LookupMX(ctx context.Context, name string) ([]*net.MX, error)
+	LookupHost(ctx context.Context, host string) ([]string, error)
+}
+
+// StaticResolver is a Resolver answering from fixed records. Names missing
+// from both maps do not exist; names in Errors fail with that error.
+type StaticResolver struct {
+	MX     map[string][]*net.MX
+	Hosts  map[string][]string
+	Errors map[string]error
+}
+
+// LookupMX returns the MX records for name
+func (r *StaticResolver) LookupMX(ctx context.Context, name string) ([]*net.MX, error) {
+	name = strings.TrimSuffix(NormalizeEmail(name), ".")
+	if err := r.Errors[name]; err != nil {
+		return nil, err
+	}
+	if records := r.MX[name]; len(records) > 0 {
+		return records, nil
+	}
+	return nil, &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+}
+
+// LookupHost returns the addresses for host
+func (r *StaticResolver) LookupHost(ctx context.Context, host string) ([]string, error) {
+	host = strings.TrimSuffix(NormalizeEmail(host), ".")
+	if err := r.Errors[host]; err != nil {
+		return nil, err
+	}
+	if addrs := r.Hosts[host]; len(addrs) > 0 {
+		return addrs, nil
+	}
+	return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+}
+
+// DomainHealth is what DNS says about a domain's ability to receive mail
+type DomainHealth struct {
+	Domain    string
+	MX        []string // MX hosts, most preferred first
+	NullMX    bool     // The domain publishes a null MX (RFC 7505) and accepts no mail
+	Fallback  bool     // There are no MX records, so mail goes to the domain's own address (RFC 5321)
+	CheckedAt time.Time
+}
+
+// AcceptsMail returns true if the domain has MX records or an address to fall back to
+func (h *DomainHealth) AcceptsMail() bool {
+	return !h.NullMX && (len(h.MX) > 0 || h.Fallback)
+}
+
+// DomainCheckerOptions configures a DomainChecker
+type DomainCheckerOptions struct {
+	Resolver    Resolver      // net.DefaultResolver if nil
+	TTL         time.Duration // How long to cache domains that accept mail
+	NegativeTTL time.Duration // How long to cache domains that do not
+}
+
+// DomainChecker checks domains' MX records, falling back to A/AAAA records
+// when there are none, and caches the answers. Failed lookups are not cached.
+type DomainChecker struct {
+	resolver    Resolver
+	ttl         time.Duration
+	negativeTTL time.Duration
+	now         func() time.Time
+
+	mu    sync.Mutex
+	cache map[string]domainHealthEntry
+}
+
+type domainHealthEntry struct {
+	health    *DomainHealth
+	expiresAt time.Time
+}
+
+// NewDomainChecker creates a checker with an empty cache
+func NewDomainChecker(opts DomainCheckerOptions) *DomainChecker {
+	if opts.Resolver == nil {
+		opts.Resolver = net.DefaultResolver
+	}
+	if opts.TTL <= 0 {
+		opts.TTL = DEFAULT_DNS_CACHE_TTL
+	}
+	if opts.NegativeTTL <= 0 {
+		opts.NegativeTTL = DEFAULT_DNS_NEGATIVE_TTL
+	}
+	return &DomainChecker{
+		resolver:    opts.Resolver,
+		ttl:         opts.TTL,
+		negativeTTL: opts.NegativeTTL,
+		now:         time.Now,
+		cache:       make(map[string]domainHealthEntry),
+	}
+}
+
+// Check looks up a domain's mail records, answering from the cache when it can.
+//
+// Parameters:
+//   - ctx: Carried to the DNS lookups
+//   - domain: The domain to check
+//
+// Returns:
+//   - *DomainHealth: The MX hosts found and whether the domain accepts mail
+//   - error: Any lookup failure other than the records not existing
+func (c *DomainChecker) Check(ctx context.Context, domain string) (*DomainHealth, error) {
+	domain = strings.TrimSuffix(NormalizeEmail(domain), ".")
+	if domain == "" {
+		return nil, errors.New("domain cannot be empty")
+	}
+
+	c.mu.Lock()
+	entry, ok := c.cache[domain]
+	c.mu.Unlock()
+	if ok && c.now().Before(entry.expiresAt) {
+		return entry.health, nil
+	}
+
+	health, err := c.lookup(ctx, domain)
+	if err != nil {
+		return nil, err
+	}
+
+	ttl := c.ttl
+	if !health.AcceptsMail() {
+		ttl = c.negativeTTL
+	}
+	c.mu.Lock()
+	c.cache[domain] = domainHealthEntry{health: health, expiresAt: health.CheckedAt.Add(ttl)}
+	c.mu.Unlock()
+	return health, nil
+}
+
+// CheckEmail checks the domain of an address
+func (c *DomainChecker) CheckEmail(ctx context.Context, email string) (*DomainHealth, error) {
+	domain := emailDomain(email)
+	if domain == "" {
+		return nil, errors.New("email has no domain")
+	}
+	return c.Check(ctx, domain)
+}
+
+func (c *DomainChecker) lookup(ctx context.Context, domain string) (*DomainHealth, error) {
+	health := &DomainHealth{Domain: domain, CheckedAt: c.now()}
+
+	records, err := c.resolver.LookupMX(ctx, domain)
+	if err != nil && !isNotFound(err) {
+		return nil, err
+	}
+	if len(records) == 1 && strings.TrimSuffix(records[0].Host, ".") == "" {
+		health.NullMX = true
+		return health, nil
+	}
+	for _, record := range records {
+		if host := strings.TrimSuffix(record.Host, "."); host != "" {
+			health.MX = append(health.MX, NormalizeEmail(host))
+		}
+	}
+	if len(health.MX) > 0 {
+		return health, nil
+	}
+
+	addrs, err := c.resolver.LookupHost(ctx, domain)
+	if err != nil && !isNotFound(err) {
+		return nil, err
+	}
+	health.Fallback = len(addrs) > 0
+	return health, nil
+}
+
+// isNotFound returns true for lookups that failed because the records do not exist
+func isNotFound(err error) bool {
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr) && dnsErr.IsNotFound
+}
+
+// dnsPreCheck checks domains before Validate calls the API. Nil disables it.
+var dnsPreCheck *DomainChecker
+
+// SetDNSPreCheck makes Validate check an address's domain before calling the
+// API and answer invalid with SUBSTATUS_NO_DNS_ENTRIES, without spending a
+// credit, when the domain cannot receive mail. Lookup failures fall through to
+// the API. Pass nil to disable it, which is the default.
+func SetDNSPreCheck(checker *DomainChecker) {
+	dnsPreCheck = checker
+}
+
+// precheckDNS returns a result for the address if its domain cannot receive mail
+func precheckDNS(ctx context.Context, email string) (*ValidateResponse, bool) {
+	health, err := dnsPreCheck.CheckEmail(ctx, email)
+	if err != nil || health.AcceptsMail() {
+		return nil, false
+	}
+	return &ValidateResponse{Email: email, Status: STATUS_INVALID, SubStatus: SUBSTATUS_NO_DNS_ENTRIES}, true
+}
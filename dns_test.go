@@ -0,0 +1,134 @@
+package emailverifygo
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func testResolver() *StaticResolver {
+	return &StaticResolver{
+		MX: map[string][]*net.MX{
+			"example.com": {{Host: "mx1.example.com.", Pref: 10}, {Host: "mx2.example.com.", Pref: 20}},
+			"nullmx.com":  {{Host: ".", Pref: 0}},
+		},
+		Hosts: map[string][]string{
+			"fallback.com": {"192.0.2.1"},
+		},
+		Errors: map[string]error{
+			"broken.com": &net.DNSError{Err: "server misbehaving", Name: "broken.com", IsTemporary: true},
+		},
+	}
+}
+
+func TestDomainChecker(t *testing.T) {
+	ctx := context.Background()
+	checker := NewDomainChecker(DomainCheckerOptions{Resolver: testResolver()})
+
+	t.Run("TestMX", func(t *testing.T) {
+		health, err := checker.Check(ctx, "Example.com.")
+		assert.Nil(t, err, "Expected no error")
+		assert.Equal(t, "example.com", health.Domain)
+		assert.Equal(t, []string{"mx1.example.com", "mx2.example.com"}, health.MX)
+		assert.True(t, health.AcceptsMail())
+	})
+
+	t.Run("TestFallback", func(t *testing.T) {
+		health, err := checker.Check(ctx, "fallback.com")
+		assert.Nil(t, err, "Expected no error")
+		assert.Empty(t, health.MX)
+		assert.True(t, health.Fallback, "Expected the A record to be used without MX records")
+		assert.True(t, health.AcceptsMail())
+	})
+
+	t.Run("TestNullMX", func(t *testing.T) {
+		health, err := checker.CheckEmail(ctx, "someone@nullmx.com")
+		assert.Nil(t, err, "Expected no error")
+		assert.True(t, health.NullMX)
+		assert.False(t, health.AcceptsMail(), "Expected a null MX to refuse mail")
+	})
+
+	t.Run("TestNoRecords", func(t *testing.T) {
+		health, err := checker.Check(ctx, "nowhere.invalid")
+		assert.Nil(t, err, "Expected a missing domain not to be an error")
+		assert.False(t, health.AcceptsMail())
+	})
+
+	t.Run("TestLookupFailure", func(t *testing.T) {
+		_, err := checker.Check(ctx, "broken.com")
+		assert.NotNil(t, err, "Expected a temporary failure to be returned")
+		_, err = checker.CheckEmail(ctx, "no-domain")
+		assert.NotNil(t, err, "Expected an error for an address without a domain")
+	})
+
+	t.Run("TestCache", func(t *testing.T) {
+		now := time.Now()
+		resolver := testResolver()
+		checker := NewDomainChecker(DomainCheckerOptions{Resolver: resolver, TTL: time.Hour, NegativeTTL: time.Minute})
+		checker.now = func() time.Time { return now }
+
+		checker.Check(ctx, "example.com")
+		checker.Check(ctx, "nowhere.invalid")
+		resolver.MX["nowhere.invalid"] = []*net.MX{{Host: "mx.nowhere.invalid.", Pref: 10}}
+		delete(resolver.MX, "example.com")
+
+		health, _ := checker.Check(ctx, "example.com")
+		assert.True(t, health.AcceptsMail(), "Expected the cached answer")
+		health, _ = checker.Check(ctx, "nowhere.invalid")
+		assert.False(t, health.AcceptsMail(), "Expected the cached answer")
+
+		now = now.Add(2 * time.Minute)
+		health, _ = checker.Check(ctx, "nowhere.invalid")
+		assert.True(t, health.AcceptsMail(), "Expected the negative answer to expire first")
+		health, _ = checker.Check(ctx, "example.com")
+		assert.True(t, health.AcceptsMail(), "Expected the positive answer to still be cached")
+	})
+
+	t.Run("TestIsNotFound", func(t *testing.T) {
+		assert.True(t, isNotFound(&net.DNSError{IsNotFound: true}))
+		assert.False(t, isNotFound(errors.New("timeout")))
+	})
+}
+
+func TestValidateWithDNSPreCheck(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	defer SetDNSPreCheck(nil)
+
+	SetApiKey("test_api_key")
+	SetDNSPreCheck(NewDomainChecker(DomainCheckerOptions{Resolver: testResolver()}))
+
+	httpmock.RegisterResponder("GET", `=~^(.*)`+ENDPOINT_VALIDATE+`(.*)\z`,
+		httpmock.NewStringResponder(200, MOCK_VALID_RESPONSE))
+
+	t.Run("TestDeadDomain", func(t *testing.T) {
+		httpmock.ZeroCallCounters()
+		response, err := Validate("someone@nullmx.com")
+
+		assert.Nil(t, err, "Expected no error")
+		assert.Equal(t, STATUS_INVALID, response.Status)
+		assert.Equal(t, SUBSTATUS_NO_DNS_ENTRIES, response.SubStatus)
+		assert.Equal(t, 0, httpmock.GetTotalCallCount(), "Expected no API call")
+	})
+
+	t.Run("TestLiveDomain", func(t *testing.T) {
+		httpmock.ZeroCallCounters()
+		_, err := Validate("valid@example.com")
+
+		assert.Nil(t, err, "Expected no error")
+		assert.Equal(t, 1, httpmock.GetTotalCallCount(), "Expected the API to be called")
+	})
+
+	t.Run("TestLookupFailureFallsThrough", func(t *testing.T) {
+		httpmock.ZeroCallCounters()
+		_, err := Validate("someone@broken.com")
+
+		assert.Nil(t, err, "Expected no error")
+		assert.Equal(t, 1, httpmock.GetTotalCallCount(), "Expected the API to be called when DNS fails")
+	})
+}
@@ -0,0 +1,117 @@
+package emailverifygo
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/idna"
+	"golang.org/x/net/publicsuffix"
+)
+
+// ErrImplausibleDomain is returned for inputs that cannot be a mail domain
+var ErrImplausibleDomain = errors.New("implausible domain")
+
+// DomainParts describes the domain extracted from a URL, address or bare host
+type DomainParts struct {
+	Host        string // The full host name, e.g. "shop.acme.co.uk"
+	Domain      string // The registrable domain, e.g. "acme.co.uk"
+	Suffix      string // The public suffix, e.g. "co.uk"
+	KnownSuffix bool   // False when the suffix is not in the Public Suffix List, in which case Domain is the whole host
+}
+
+// ParseDomain extracts the registrable domain from a company URL, an email
+// address or a bare host name. Schemes, credentials, ports, paths and a leading
+// "www." are stripped, internationalized names are converted to punycode, and
+// the Public Suffix List decides how many labels make up the registrable
+// domain, so "https://www.acme.co.uk/about" gives "acme.co.uk". Hosts under a
+// suffix the list does not know are kept whole rather than guessed at.
+// Inputs that cannot be a domain, such as company names, IP addresses or bare
+// public suffixes, fail with ErrImplausibleDomain.
+func ParseDomain(input string) (*DomainParts, error) {
+	host := strings.TrimSpace(input)
+	if at := strings.LastIndex(host, "@"); at >= 0 && !strings.Contains(host, "/") {
+		host = host[at+1:]
+	}
+	if !strings.Contains(host, "://") {
+		host = "http://" + host
+	}
+	parsed, err := url.Parse(host)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q", ErrImplausibleDomain, input)
+	}
+	host = strings.TrimSuffix(parsed.Hostname(), ".")
+	if net.ParseIP(host) == nil && host != "" {
+		if host, err = idna.Lookup.ToASCII(host); err != nil {
+			return nil, fmt.Errorf("%w: %q is not a valid host name", ErrImplausibleDomain, input)
+		}
+	}
+	host = strings.ToLower(host)
+
+	if err := checkHostName(host); err != nil {
+		return nil, fmt.Errorf("%w: %q %s", ErrImplausibleDomain, input, err)
+	}
+
+	// Private suffixes such as github.io are never ICANN, but always have several labels
+	suffix, icann := publicsuffix.PublicSuffix(host)
+	known := icann || strings.Contains(suffix, ".")
+	if suffix == host {
+		return nil, fmt.Errorf("%w: %q is a public suffix", ErrImplausibleDomain, input)
+	}
+
+	parts := &DomainParts{Host: strings.TrimPrefix(host, "www."), Suffix: suffix, KnownSuffix: known}
+	if !known {
+		parts.Domain = parts.Host
+		return parts, nil
+	}
+	if parts.Domain, err = publicsuffix.EffectiveTLDPlusOne(host); err != nil {
+		return nil, fmt.Errorf("%w: %q %s", ErrImplausibleDomain, input, err)
+	}
+	return parts, nil
+}
+
+// ExtractDomain returns the registrable domain of a company URL, email address
+// or host name, as described by ParseDomain
+func ExtractDomain(input string) (string, error) {
+	parts, err := ParseDomain(input)
+	if err != nil {
+		return "", err
+	}
+	return parts.Domain, nil
+}
+
+// checkHostName reports why a lower-cased host cannot be a mail domain
+func checkHostName(host string) error {
+	switch {
+	case host == "":
+		return fmt.Errorf("is empty")
+	case net.ParseIP(host) != nil:
+		return fmt.Errorf("is an IP address")
+	case len(host) > 253:
+		return fmt.Errorf("is too long")
+	case !strings.Contains(host, "."):
+		return fmt.Errorf("has no top-level domain")
+	}
+
+	labels := strings.Split(host, ".")
+	for _, label := range labels {
+		if label == "" || len(label) > 63 {
+			return fmt.Errorf("has an empty or too long label")
+		}
+		if strings.HasPrefix(label, "-") || strings.HasSuffix(label, "-") {
+			return fmt.Errorf("has a label starting or ending with a hyphen")
+		}
+		for _, r := range label {
+			if !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-') {
+				return fmt.Errorf("contains %q", r)
+			}
+		}
+	}
+	tld := labels[len(labels)-1]
+	if strings.Trim(tld, "0123456789") == "" {
+		return fmt.Errorf("has a numeric top-level domain")
+	}
+	return nil
+}
@@ -0,0 +1,198 @@
+package emailverifygo
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Kinds of domain-level knowledge recorded by DomainIntelligence
+const (
+	DOMAIN_KIND_CATCH_ALL = "catch_all"      // The domain accepts every address
+	DOMAIN_KIND_NO_DNS    = "no_dns_entries" // The domain has no DNS entries
+	DOMAIN_KIND_BLOCKED   = "blocked_domain" // The domain is blocked
+)
+
+// Default times to live used by NewDomainIntelligence when an option is left at zero
+const (
+	DEFAULT_CATCH_ALL_TTL = 7 * 24 * time.Hour
+	DEFAULT_NO_DNS_TTL    = 24 * time.Hour
+	DEFAULT_BLOCKED_TTL   = 7 * 24 * time.Hour
+)
+
+// DomainKnowledge is what a validation result revealed about its whole domain
+type DomainKnowledge struct {
+	Domain     string    `json:"domain"`
+	Kind       string    `json:"kind"`       // One of the DOMAIN_KIND_* values
+	Status     string    `json:"status"`     // The status of the result that revealed it
+	SubStatus  string    `json:"sub_status"` // The sub-status of the result that revealed it
+	ObservedAt time.Time `json:"observed_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+// IsDead returns true for domains that cannot receive mail
+func (k DomainKnowledge) IsDead() bool {
+	return k.Kind == DOMAIN_KIND_NO_DNS || k.Kind == DOMAIN_KIND_BLOCKED
+}
+
+// DomainIntelligenceOptions configures a DomainIntelligence
+type DomainIntelligenceOptions struct {
+	CatchAllTTL time.Duration
+	NoDNSTTL    time.Duration
+	BlockedTTL  time.Duration
+	// SkipCatchAll answers Validate for addresses on known catch-all domains
+	// from the store, without spending a credit
+	SkipCatchAll bool
+	// SkipDead answers Validate for addresses on domains known to have no DNS
+	// entries or to be blocked from the store, without spending a credit
+	SkipDead bool
+}
+
+// DomainIntelligence remembers domains that validation showed to be catch-all,
+// without DNS entries or blocked. Set it with SetDomainIntelligence to have
+// Validate and GetBatchResults record what they see, and to short-circuit
+// Validate for known domains. Rank orders addresses so those on known domains
+// go last.
+type DomainIntelligence struct {
+	opts DomainIntelligenceOptions
+	now  func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]DomainKnowledge
+}
+
+// NewDomainIntelligence creates an empty store
+func NewDomainIntelligence(opts DomainIntelligenceOptions) *DomainIntelligence {
+	if opts.CatchAllTTL <= 0 {
+		opts.CatchAllTTL = DEFAULT_CATCH_ALL_TTL
+	}
+	if opts.NoDNSTTL <= 0 {
+		opts.NoDNSTTL = DEFAULT_NO_DNS_TTL
+	}
+	if opts.BlockedTTL <= 0 {
+		opts.BlockedTTL = DEFAULT_BLOCKED_TTL
+	}
+	return &DomainIntelligence{opts: opts, now: time.Now, entries: make(map[string]DomainKnowledge)}
+}
+
+// Observe records what a validation result says about its domain. A valid
+// result clears what was known, since the domain neither accepts everything
+// nor is dead.
+func (d *DomainIntelligence) Observe(email, status, subStatus string) {
+	domain := emailDomain(email)
+	if domain == "" {
+		return
+	}
+
+	var kind string
+	var ttl time.Duration
+	switch {
+	case status == STATUS_CATCH_ALL:
+		kind, ttl = DOMAIN_KIND_CATCH_ALL, d.opts.CatchAllTTL
+	case subStatus == SUBSTATUS_NO_DNS_ENTRIES:
+		kind, ttl = DOMAIN_KIND_NO_DNS, d.opts.NoDNSTTL
+	case subStatus == SUBSTATUS_BLOCKED_DOMAIN:
+		kind, ttl = DOMAIN_KIND_BLOCKED, d.opts.BlockedTTL
+	case status == STATUS_VALID:
+		d.Forget(domain)
+		return
+	default:
+		return
+	}
+
+	now := d.now()
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.entries[domain] = DomainKnowledge{
+		Domain:     domain,
+		Kind:       kind,
+		Status:     status,
+		SubStatus:  subStatus,
+		ObservedAt: now,
+		ExpiresAt:  now.Add(ttl),
+	}
+}
+
+// Lookup returns what is known about a domain, if it has not expired
+func (d *DomainIntelligence) Lookup(domain string) (DomainKnowledge, bool) {
+	domain = NormalizeEmail(domain)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	knowledge, ok := d.entries[domain]
+	if !ok {
+		return DomainKnowledge{}, false
+	}
+	if d.now().After(knowledge.ExpiresAt) {
+		delete(d.entries, domain)
+		return DomainKnowledge{}, false
+	}
+	return knowledge, true
+}
+
+// Forget drops what is known about a domain
+func (d *DomainIntelligence) Forget(domain string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.entries, NormalizeEmail(domain))
+}
+
+// Rank returns the addresses reordered so those on known catch-all domains
+// come after the others, and those on dead domains last. The order is
+// otherwise kept, so the most useful addresses are validated first.
+func (d *DomainIntelligence) Rank(emails []string) []string {
+	ranked := append([]string(nil), emails...)
+	ranks := make(map[string]int, len(ranked))
+	for _, email := range ranked {
+		ranks[email] = d.rank(email)
+	}
+	sort.SliceStable(ranked, func(i, j int) bool { return ranks[ranked[i]] < ranks[ranked[j]] })
+	return ranked
+}
+
+func (d *DomainIntelligence) rank(email string) int {
+	knowledge, ok := d.Lookup(emailDomain(email))
+	switch {
+	case !ok:
+		return 0
+	case knowledge.IsDead():
+		return 2
+	default:
+		return 1
+	}
+}
+
+// answer returns a result for the address from what is known about its
+// domain, when the options allow skipping the API
+func (d *DomainIntelligence) answer(email string) (*ValidateResponse, bool) {
+	knowledge, ok := d.Lookup(emailDomain(email))
+	if !ok || (knowledge.IsDead() && !d.opts.SkipDead) || (!knowledge.IsDead() && !d.opts.SkipCatchAll) {
+		return nil, false
+	}
+	return &ValidateResponse{Email: email, Status: knowledge.Status, SubStatus: knowledge.SubStatus}, true
+}
+
+// domainIntelligence records domain-level results. Nil disables it.
+var domainIntelligence *DomainIntelligence
+
+// SetDomainIntelligence sets the store Validate and GetBatchResults record
+// domain-level results in and may answer from. Pass nil to disable it, which is the default.
+func SetDomainIntelligence(store *DomainIntelligence) {
+	domainIntelligence = store
+}
+
+func observeDomain(email, status, subStatus string) {
+	if domainIntelligence != nil {
+		domainIntelligence.Observe(email, status, subStatus)
+	}
+}
+
+// emailDomain returns the normalized domain of an address, or "" if it has none
+func emailDomain(email string) string {
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return ""
+	}
+	return NormalizeEmail(email[at+1:])
+}
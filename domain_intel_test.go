@@ -0,0 +1,126 @@
+package emailverifygo
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDomainIntelligence(t *testing.T) {
+	t.Run("TestObserve", func(t *testing.T) {
+		store := NewDomainIntelligence(DomainIntelligenceOptions{})
+		store.Observe("a@CatchAll.io", STATUS_CATCH_ALL, "")
+		store.Observe("b@dead.com", STATUS_INVALID, SUBSTATUS_NO_DNS_ENTRIES)
+		store.Observe("c@blocked.com", STATUS_DO_NOT_MAIL, SUBSTATUS_BLOCKED_DOMAIN)
+		store.Observe("d@example.com", STATUS_INVALID, SUBSTATUS_MAILBOX_NOT_FOUND)
+
+		knowledge, ok := store.Lookup("catchall.io")
+		assert.True(t, ok, "Expected the catch-all domain to be known")
+		assert.Equal(t, DOMAIN_KIND_CATCH_ALL, knowledge.Kind)
+		assert.False(t, knowledge.IsDead())
+
+		knowledge, _ = store.Lookup("dead.com")
+		assert.Equal(t, DOMAIN_KIND_NO_DNS, knowledge.Kind)
+		assert.True(t, knowledge.IsDead())
+		knowledge, _ = store.Lookup("blocked.com")
+		assert.Equal(t, DOMAIN_KIND_BLOCKED, knowledge.Kind)
+
+		_, ok = store.Lookup("example.com")
+		assert.False(t, ok, "Expected a mailbox-level result to teach nothing about the domain")
+
+		store.Observe("e@catchall.io", STATUS_VALID, "")
+		_, ok = store.Lookup("catchall.io")
+		assert.False(t, ok, "Expected a valid result to clear the domain")
+	})
+
+	t.Run("TestExpiry", func(t *testing.T) {
+		now := time.Now()
+		store := NewDomainIntelligence(DomainIntelligenceOptions{NoDNSTTL: time.Hour})
+		store.now = func() time.Time { return now }
+		store.Observe("b@dead.com", STATUS_INVALID, SUBSTATUS_NO_DNS_ENTRIES)
+		store.Observe("a@catchall.io", STATUS_CATCH_ALL, "")
+
+		now = now.Add(2 * time.Hour)
+		_, ok := store.Lookup("dead.com")
+		assert.False(t, ok, "Expected the entry to expire after its TTL")
+		_, ok = store.Lookup("catchall.io")
+		assert.True(t, ok, "Expected the default catch-all TTL to still hold")
+	})
+
+	t.Run("TestRank", func(t *testing.T) {
+		store := NewDomainIntelligence(DomainIntelligenceOptions{})
+		store.Observe("x@catchall.io", STATUS_CATCH_ALL, "")
+		store.Observe("x@dead.com", STATUS_INVALID, SUBSTATUS_NO_DNS_ENTRIES)
+
+		emails := []string{"a@dead.com", "b@catchall.io", "c@example.com", "d@dead.com", "e@example.com"}
+		assert.Equal(t, []string{"c@example.com", "e@example.com", "b@catchall.io", "a@dead.com", "d@dead.com"}, store.Rank(emails))
+		assert.Equal(t, "a@dead.com", emails[0], "Expected the input to be left alone")
+	})
+}
+
+func TestValidateWithDomainIntelligence(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	defer SetDomainIntelligence(nil)
+
+	SetApiKey("test_api_key")
+
+	httpmock.RegisterResponder("GET", `=~^(.*)`+ENDPOINT_VALIDATE+`(.*)\z`,
+		func(r *http.Request) (*http.Response, error) {
+			email := r.URL.Query().Get("email")
+			status, subStatus := STATUS_CATCH_ALL, ""
+			if email == "someone@dead.com" {
+				status, subStatus = STATUS_INVALID, SUBSTATUS_NO_DNS_ENTRIES
+			}
+			return httpmock.NewStringResponse(200, fmt.Sprintf(`{"email": %q, "status": %q, "sub_status": %q}`, email, status, subStatus)), nil
+		},
+	)
+	httpmock.RegisterResponder("GET", `=~^(.*)`+ENDPOINT_BATCH_RESULT+`(.*)\z`,
+		httpmock.NewStringResponder(200, MOCK_BATCH_RESULTS_RESPONSE))
+
+	t.Run("TestShortCircuitDead", func(t *testing.T) {
+		SetDomainIntelligence(NewDomainIntelligence(DomainIntelligenceOptions{SkipDead: true}))
+		httpmock.ZeroCallCounters()
+
+		_, err := Validate("someone@dead.com")
+		assert.Nil(t, err, "Expected no error")
+		response, err := Validate("other@dead.com")
+		assert.Nil(t, err, "Expected no error")
+		assert.Equal(t, "other@dead.com", response.Email)
+		assert.Equal(t, STATUS_INVALID, response.Status)
+		assert.Equal(t, SUBSTATUS_NO_DNS_ENTRIES, response.SubStatus)
+		assert.Equal(t, 1, httpmock.GetTotalCallCount(), "Expected the second address to be answered from the store")
+
+		_, err = Validate("a@catchall.io")
+		assert.Nil(t, err, "Expected no error")
+		_, err = Validate("b@catchall.io")
+		assert.Nil(t, err, "Expected no error")
+		assert.Equal(t, 3, httpmock.GetTotalCallCount(), "Expected catch-all domains to be validated without SkipCatchAll")
+	})
+
+	t.Run("TestShortCircuitCatchAll", func(t *testing.T) {
+		SetDomainIntelligence(NewDomainIntelligence(DomainIntelligenceOptions{SkipCatchAll: true}))
+		httpmock.ZeroCallCounters()
+
+		Validate("a@catchall.io")
+		response, err := Validate("b@catchall.io")
+		assert.Nil(t, err, "Expected no error")
+		assert.Equal(t, STATUS_CATCH_ALL, response.Status)
+		assert.Equal(t, 1, httpmock.GetTotalCallCount(), "Expected the second address to be answered from the store")
+	})
+
+	t.Run("TestObserveBatchResults", func(t *testing.T) {
+		store := NewDomainIntelligence(DomainIntelligenceOptions{})
+		SetDomainIntelligence(store)
+
+		_, err := GetBatchResults(12345)
+		assert.Nil(t, err, "Expected no error")
+		knowledge, ok := store.Lookup("example.com")
+		assert.True(t, ok, "Expected batch results to be observed")
+		assert.Equal(t, DOMAIN_KIND_NO_DNS, knowledge.Kind)
+	})
+}
@@ -0,0 +1,86 @@
+package emailverifygo
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDomain(t *testing.T) {
+	t.Run("TestRegistrableDomain", func(t *testing.T) {
+		for input, domain := range map[string]string{
+			"https://www.acme.co.uk/about":  "acme.co.uk",
+			"acme.com":                      "acme.com",
+			"HTTP://Shop.Acme.COM:8080/x?y": "acme.com",
+			"jane@mail.acme.com.au":         "acme.com.au",
+			"www.acme.io.":                  "acme.io",
+			"portal.acme.co.jp":             "acme.co.jp",
+			"acme.github.io":                "acme.github.io",
+			"foo.bar.ck":                    "foo.bar.ck",
+			"www.ck":                        "www.ck",
+			"nus.edu.sg":                    "nus.edu.sg",
+			"www.uct.ac.za":                 "uct.ac.za",
+			"cs.auckland.ac.nz":             "auckland.ac.nz",
+			"police.gov.in":                 "police.gov.in",
+			"https://www.münchen.de/":       "xn--mnchen-3ya.de",
+		} {
+			extracted, err := ExtractDomain(input)
+			assert.Nil(t, err, "Expected %q to be accepted", input)
+			assert.Equal(t, domain, extracted, "Unexpected domain for %q", input)
+		}
+	})
+
+	t.Run("TestParts", func(t *testing.T) {
+		parts, err := ParseDomain("https://www.shop.acme.co.uk/")
+		assert.Nil(t, err)
+		assert.Equal(t, &DomainParts{Host: "shop.acme.co.uk", Domain: "acme.co.uk", Suffix: "co.uk", KnownSuffix: true}, parts)
+
+		parts, err = ParseDomain("www.mail.acme.unlisted")
+		assert.Nil(t, err, "Expected unknown suffixes to be accepted")
+		assert.Equal(t, "mail.acme.unlisted", parts.Domain, "Expected the whole host under an unknown suffix")
+		assert.False(t, parts.KnownSuffix, "Expected the unknown suffix to be flagged")
+	})
+
+	t.Run("TestImplausible", func(t *testing.T) {
+		for _, input := range []string{
+			"",
+			"Acme Inc",
+			"acme",
+			"co.uk",
+			"edu.sg",
+			"192.168.1.10",
+			"https://[::1]/",
+			"-acme-.com",
+			"acme..com",
+			"acme_corp.com",
+		} {
+			_, err := ExtractDomain(input)
+			assert.ErrorIs(t, err, ErrImplausibleDomain, "Expected %q to be rejected", input)
+		}
+	})
+}
+
+func TestFindEmailDomainNormalization(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	SetApiKey("test_api_key")
+
+	var domains []string
+	httpmock.RegisterResponder("GET", `=~^(.*)`+ENDPOINT_EMAIL_FINDER+`(.*)\z`,
+		func(r *http.Request) (*http.Response, error) {
+			domains = append(domains, r.URL.Query().Get("domain"))
+			return httpmock.NewStringResponse(200, MOCK_FINDER_RESPONSE), nil
+		},
+	)
+
+	_, err := FindEmail("John Doe", "https://www.example.co.uk/team")
+	assert.Nil(t, err, "Expected no error")
+	assert.Equal(t, []string{"example.co.uk"}, domains, "Expected the registrable domain to be sent")
+
+	_, err = FindEmail("John Doe", "Example Ltd")
+	assert.ErrorIs(t, err, ErrImplausibleDomain)
+	assert.Len(t, domains, 1, "Expected no request for an implausible domain")
+}
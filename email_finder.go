@@ -1,6 +1,7 @@
 package emailverifygo
 
 import (
+	"context"
 	"fmt"
 	"net/url"
 )
@@ -20,7 +21,8 @@ func (f *FindEmailResponse) IsFound() bool {
 //
 // Parameters:
 //   - name: The full name of the person to search for (e.g., "John Smith")
-//   - domain: The domain to search for the email on
+//   - domain: The domain to search for the email on. A company URL or host name
+//     is reduced to its registrable domain, see ExtractDomain.
 //
 // Returns:
 //   - *FindEmailResponse: The email finder result
@@ -28,11 +30,28 @@ func (f *FindEmailResponse) IsFound() bool {
 //
 // API Reference: GET /api/v1/finder
 func FindEmail(name, domain string) (*FindEmailResponse, error) {
+	return FindEmailContext(context.Background(), name, domain)
+}
+
+// FindEmailContext is like FindEmail but carries ctx to the HTTP request and tracing spans
+func FindEmailContext(ctx context.Context, name, domain string) (response *FindEmailResponse, err error) {
+	ctx, span := startSpan(ctx, SPAN_FIND_EMAIL, Attr("endpoint", ENDPOINT_EMAIL_FINDER), Attr("domain", domain))
+	defer func() {
+		finishSpan(span, err)
+	}()
+
 	if name == "" || domain == "" {
 		return nil, fmt.Errorf("Both name and domain are required")
 	}
+
+	// Reduce URLs and hosts to the registrable domain, and spend no credit on implausible ones
+	domain, err = ExtractDomain(domain)
+	if err != nil {
+		return nil, err
+	}
+	span.SetAttributes(Attr("domain", domain))
 	
-	response := &FindEmailResponse{}
+	response = &FindEmailResponse{}
 
 	request_parameters := url.Values{}
 	request_parameters.Set("name", name)
@@ -43,6 +62,10 @@ func FindEmail(name, domain string) (*FindEmailResponse, error) {
 		return response, fmt.Errorf("failed to prepare URL: %w", err)
 	}
 
-	err = DoGetRequest(url_to_request, response)
+	err = doGetRequest(ctx, url_to_request, response)
+	if err == nil {
+		observeCredits(ENDPOINT_EMAIL_FINDER, CREDITS_PER_FINDER)
+		span.SetAttributes(Attr("status", response.Status))
+	}
 	return response, err
 }
@@ -0,0 +1,123 @@
+package emailverifygo
+
+import (
+	"context"
+)
+
+// EstimateOptions configures a dry run
+type EstimateOptions struct {
+	// Cache answers addresses without spending credits. EstimateBatch ignores
+	// it, since batches always send every address.
+	Cache ValidationCache
+	// CheckBalance fetches the account balance, which costs no credits, to
+	// report whether it covers the estimate
+	CheckBalance bool
+}
+
+// CostEstimate reports what a validation run would send and spend
+type CostEstimate struct {
+	Total      int               `json:"total"`      // Number of addresses given
+	Duplicates []DuplicateGroup  `json:"duplicates"` // Inputs that collapse into one address
+	Invalid    []EmailBatchError `json:"invalid"`    // Addresses failing the local syntax check
+	Cached     []string          `json:"cached"`     // Addresses the cache would answer
+	ToSend     []string          `json:"to_send"`    // Normalized addresses that would be sent to the API
+	Credits    int               `json:"credits"`    // Estimated credits the run would consume
+
+	// Balance is the account balance, nil unless EstimateOptions.CheckBalance is set
+	Balance *AccountBalanceResponse `json:"balance,omitempty"`
+	// Covered is true when the credits available right now cover the estimate
+	Covered bool `json:"covered"`
+}
+
+// EstimateValidation runs the local checks a validation run would go through,
+// without validating anything: addresses are normalized, deduplicated, syntax
+// checked and looked up in the cache. It reports how many addresses would be
+// sent and the credits that would cost. Use it as a dry run before a run of
+// Validate or ValidateWithCache calls that skips duplicates and malformed
+// addresses; for ValidateBatch, which sends every address, use EstimateBatch.
+//
+// Parameters:
+//   - ctx: Carried to the balance request, if any
+//   - emails: The addresses that would be validated
+//   - opts: The cache to consult and whether to check the balance
+//
+// Returns:
+//   - *CostEstimate: The estimate
+//   - error: Any error fetching the balance
+func EstimateValidation(ctx context.Context, emails []string, opts EstimateOptions) (*CostEstimate, error) {
+	estimate := &CostEstimate{Total: len(emails)}
+
+	var order []string
+	inputs := make(map[string][]string)
+	for _, email := range emails {
+		normalized := NormalizeEmail(email)
+		if _, seen := inputs[normalized]; !seen {
+			order = append(order, normalized)
+		}
+		inputs[normalized] = append(inputs[normalized], email)
+	}
+
+	for _, address := range order {
+		if group := inputs[address]; len(group) > 1 {
+			estimate.Duplicates = append(estimate.Duplicates, DuplicateGroup{Address: address, Inputs: group})
+		}
+
+		if err := CheckEmailSyntax(address); err != nil {
+			estimate.Invalid = append(estimate.Invalid, EmailBatchError{Address: address, Error: err.Error()})
+			continue
+		}
+		if opts.Cache != nil {
+			if _, ok := opts.Cache.Get(address); ok {
+				estimate.Cached = append(estimate.Cached, address)
+				continue
+			}
+		}
+		estimate.ToSend = append(estimate.ToSend, address)
+	}
+	estimate.Credits = len(estimate.ToSend) * CREDITS_PER_VALIDATION
+
+	if !opts.CheckBalance {
+		return estimate, nil
+	}
+	return estimate, checkEstimateBalance(ctx, estimate)
+}
+
+// EstimateBatch is a dry run for ValidateBatch. ValidateBatch sends every
+// address as given, so every address counts towards ToSend and Credits, the
+// most the batch can cost. Duplicates and Invalid list what the API may still
+// drop or reject, which would make the batch cheaper. The cache is not consulted.
+//
+// Parameters:
+//   - ctx: Carried to the balance request, if any
+//   - emails: The addresses that would be passed to ValidateBatch
+//   - opts: Whether to check the balance
+//
+// Returns:
+//   - *CostEstimate: The estimate
+//   - error: Any error fetching the balance
+func EstimateBatch(ctx context.Context, emails []string, opts EstimateOptions) (*CostEstimate, error) {
+	estimate, _ := EstimateValidation(ctx, emails, EstimateOptions{})
+	estimate.ToSend = append([]string(nil), emails...)
+	estimate.Credits = len(emails) * CREDITS_PER_VALIDATION
+
+	if !opts.CheckBalance {
+		return estimate, nil
+	}
+	return estimate, checkEstimateBalance(ctx, estimate)
+}
+
+// EstimateBatchItems is like EstimateBatch for the items passed to ValidateBatchItems
+func EstimateBatchItems(ctx context.Context, items []BatchItem, opts EstimateOptions) (*CostEstimate, error) {
+	return EstimateBatch(ctx, batchItemAddresses(items), opts)
+}
+
+// checkEstimateBalance fetches the balance and records whether it covers the estimate
+func checkEstimateBalance(ctx context.Context, estimate *CostEstimate) error {
+	balance, err := GetAccountBalanceContext(ctx)
+	if err != nil {
+		return err
+	}
+	estimate.Balance = balance
+	estimate.Covered = balance.CreditsAvailable() >= estimate.Credits
+	return nil
+}
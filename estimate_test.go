@@ -0,0 +1,91 @@
+package emailverifygo
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEstimateValidation(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	SetApiKey("test_api_key")
+
+	httpmock.RegisterResponder("GET", `=~^(.*)`+ENDPOINT_ACCOUNT_BALANCE+`(.*)\z`,
+		httpmock.NewStringResponder(200, MOCK_ACCOUNT_BALANCE_APPSUMO_RESPONSE))
+
+	ctx := context.Background()
+	emails := []string{
+		"John@Example.com",
+		" john@example.com",
+		"jane@example.com",
+		"not-an-email",
+		"cached@example.com",
+	}
+
+	t.Run("TestLocalChecks", func(t *testing.T) {
+		cache := NewMemoryValidationCache(0)
+		cache.Set("cached@example.com", &ValidateResponse{Email: "cached@example.com", Status: STATUS_VALID})
+
+		estimate, err := EstimateValidation(ctx, emails, EstimateOptions{Cache: cache})
+
+		assert.Nil(t, err, "Expected no error")
+		assert.Equal(t, 0, httpmock.GetTotalCallCount(), "Expected a dry run to make no API call")
+		assert.Equal(t, 5, estimate.Total)
+		if assert.Len(t, estimate.Duplicates, 1) {
+			assert.Equal(t, "john@example.com", estimate.Duplicates[0].Address)
+			assert.Len(t, estimate.Duplicates[0].Inputs, 2)
+		}
+		if assert.Len(t, estimate.Invalid, 1) {
+			assert.Equal(t, "not-an-email", estimate.Invalid[0].Address)
+		}
+		assert.Equal(t, []string{"cached@example.com"}, estimate.Cached)
+		assert.Equal(t, []string{"john@example.com", "jane@example.com"}, estimate.ToSend)
+		assert.Equal(t, 2, estimate.Credits, "Expected one credit per address sent")
+		assert.Nil(t, estimate.Balance, "Expected no balance unless asked for")
+	})
+
+	t.Run("TestCheckBalance", func(t *testing.T) {
+		estimate, err := EstimateValidation(ctx, emails, EstimateOptions{CheckBalance: true})
+
+		assert.Nil(t, err, "Expected no error")
+		assert.Equal(t, 3, estimate.Credits, "Expected the cached address to be sent without a cache")
+		assert.NotNil(t, estimate.Balance)
+		assert.True(t, estimate.Covered, "Expected 150 available credits to cover the estimate")
+
+		many := make([]string, 0, 200)
+		for i := 0; i < 200; i++ {
+			many = append(many, fmt.Sprintf("user%d@example.com", i))
+		}
+		estimate, err = EstimateBatchItems(ctx, itemsFor(many), EstimateOptions{CheckBalance: true})
+		assert.Nil(t, err)
+		assert.Equal(t, 200, estimate.Credits)
+		assert.False(t, estimate.Covered, "Expected 150 available credits not to cover 200")
+	})
+
+	t.Run("TestBatchCountsEveryAddress", func(t *testing.T) {
+		cache := NewMemoryValidationCache(0)
+		cache.Set("cached@example.com", &ValidateResponse{Email: "cached@example.com", Status: STATUS_VALID})
+
+		estimate, err := EstimateBatch(ctx, emails, EstimateOptions{Cache: cache})
+
+		assert.Nil(t, err, "Expected no error")
+		assert.Equal(t, emails, estimate.ToSend, "Expected every address to be sent as given")
+		assert.Equal(t, 5, estimate.Credits, "Expected the batch to be estimated at its most")
+		assert.Len(t, estimate.Duplicates, 1, "Expected duplicates to still be reported")
+		assert.Len(t, estimate.Invalid, 1, "Expected malformed addresses to still be reported")
+		assert.Empty(t, estimate.Cached, "Expected batches not to consult the cache")
+	})
+}
+
+func itemsFor(emails []string) []BatchItem {
+	items := make([]BatchItem, len(emails))
+	for i, email := range emails {
+		items[i] = BatchItem{Address: email}
+	}
+	return items
+}
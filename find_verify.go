@@ -0,0 +1,84 @@
+package emailverifygo
+
+import (
+	"context"
+	"fmt"
+)
+
+// Confidence levels reported by FindAndVerify
+const (
+	CONFIDENCE_HIGH       = "high"       // Found and validated as deliverable
+	CONFIDENCE_MEDIUM     = "medium"     // Found, but the domain is catch-all, the address role-based or the status unknown
+	CONFIDENCE_LOW        = "low"        // Found, but validation says not to mail it
+	CONFIDENCE_NONE       = "none"       // Not found
+	CONFIDENCE_UNVERIFIED = "unverified" // Found, but validation failed with an error
+)
+
+// FindAndVerifyResponse merges an email finder lookup with the validation of the address it found
+type FindAndVerifyResponse struct {
+	Name       string             `json:"name"`
+	Domain     string             `json:"domain"`
+	Email      string             `json:"email"`      // The address found, empty if none
+	Finder     *FindEmailResponse `json:"finder"`     // The email finder result
+	Validation *ValidateResponse  `json:"validation"` // The validation result, nil if nothing was found or validation failed
+	Confidence string             `json:"confidence"` // One of the CONFIDENCE_* levels
+}
+
+// IsDeliverable returns true when the found address validated as deliverable
+func (r *FindAndVerifyResponse) IsDeliverable() bool {
+	return r.Confidence == CONFIDENCE_HIGH
+}
+
+// FindAndVerify finds a person's email address and validates it, spending one
+// finder credit and, when an address is found, one validation credit
+//
+// Parameters:
+//   - name: The full name of the person to search for (e.g., "John Smith")
+//   - domain: The domain to search for the email on
+//
+// Returns:
+//   - *FindAndVerifyResponse: Both results and the confidence in the address
+//   - error: Any error from either call. When validation fails the response
+//     still holds the finder result, with CONFIDENCE_UNVERIFIED.
+func FindAndVerify(name, domain string) (*FindAndVerifyResponse, error) {
+	return FindAndVerifyContext(context.Background(), name, domain)
+}
+
+// FindAndVerifyContext is like FindAndVerify but carries ctx to the HTTP requests and tracing spans
+func FindAndVerifyContext(ctx context.Context, name, domain string) (*FindAndVerifyResponse, error) {
+	finder, err := FindEmailContext(ctx, name, domain)
+	if err != nil {
+		return nil, err
+	}
+	return verifyFound(ctx, name, domain, finder)
+}
+
+// verifyFound validates the address in a finder result and merges both outcomes
+func verifyFound(ctx context.Context, name, domain string, finder *FindEmailResponse) (*FindAndVerifyResponse, error) {
+	response := &FindAndVerifyResponse{Name: name, Domain: domain, Finder: finder, Confidence: CONFIDENCE_NONE}
+	if !finder.IsFound() {
+		return response, nil
+	}
+	response.Email = finder.Email
+
+	validation, err := ValidateContext(ctx, finder.Email)
+	if err != nil {
+		response.Confidence = CONFIDENCE_UNVERIFIED
+		return response, fmt.Errorf("failed to validate found email: %w", err)
+	}
+	response.Validation = validation
+	response.Confidence = confidenceFor(validation)
+	return response, nil
+}
+
+// confidenceFor maps a validation status to a confidence level
+func confidenceFor(validation *ValidateResponse) string {
+	switch validation.Status {
+	case STATUS_VALID:
+		return CONFIDENCE_HIGH
+	case STATUS_CATCH_ALL, STATUS_ROLE_BASED, STATUS_UNKNOWN:
+		return CONFIDENCE_MEDIUM
+	default:
+		return CONFIDENCE_LOW
+	}
+}
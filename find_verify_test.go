@@ -0,0 +1,75 @@
+package emailverifygo
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindAndVerify(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	SetApiKey("test_api_key")
+
+	validationStatus := STATUS_VALID
+	httpmock.RegisterResponder("GET", `=~^(.*)`+ENDPOINT_EMAIL_FINDER+`(.*)\z`,
+		func(r *http.Request) (*http.Response, error) {
+			if r.URL.Query().Get("domain") == "example.com" {
+				return httpmock.NewStringResponse(200, MOCK_FINDER_RESPONSE), nil
+			}
+			return httpmock.NewStringResponse(200, MOCK_FINDER_NOT_FOUND_RESPONSE), nil
+		},
+	)
+	httpmock.RegisterResponder("GET", `=~^(.*)`+ENDPOINT_VALIDATE+`(.*)\z`,
+		func(r *http.Request) (*http.Response, error) {
+			if validationStatus == "" {
+				return httpmock.NewStringResponse(500, MOCK_ERROR_RESPONSE), nil
+			}
+			body := fmt.Sprintf(`{"email": %q, "status": %q, "sub_status": ""}`, r.URL.Query().Get("email"), validationStatus)
+			return httpmock.NewStringResponse(200, body), nil
+		},
+	)
+
+	t.Run("TestConfidence", func(t *testing.T) {
+		for status, confidence := range map[string]string{
+			STATUS_VALID:       CONFIDENCE_HIGH,
+			STATUS_CATCH_ALL:   CONFIDENCE_MEDIUM,
+			STATUS_UNKNOWN:     CONFIDENCE_MEDIUM,
+			STATUS_INVALID:     CONFIDENCE_LOW,
+			STATUS_DO_NOT_MAIL: CONFIDENCE_LOW,
+		} {
+			validationStatus = status
+			result, err := FindAndVerify("John Doe", "example.com")
+
+			assert.Nil(t, err, "Expected no error")
+			assert.Equal(t, "john.doe@example.com", result.Email)
+			assert.Equal(t, status, result.Validation.Status)
+			assert.Equal(t, confidence, result.Confidence, "Unexpected confidence for status %s", status)
+		}
+	})
+
+	t.Run("TestNotFound", func(t *testing.T) {
+		httpmock.ZeroCallCounters()
+		result, err := FindAndVerify("John Doe", "unknown.com")
+
+		assert.Nil(t, err, "Expected no error")
+		assert.Equal(t, CONFIDENCE_NONE, result.Confidence)
+		assert.Empty(t, result.Email)
+		assert.Nil(t, result.Validation)
+		assert.Equal(t, 1, httpmock.GetTotalCallCount(), "Expected no validation when nothing was found")
+	})
+
+	t.Run("TestValidationError", func(t *testing.T) {
+		validationStatus = ""
+		result, err := FindAndVerify("John Doe", "example.com")
+
+		assert.NotNil(t, err, "Expected the validation error")
+		assert.Equal(t, CONFIDENCE_UNVERIFIED, result.Confidence)
+		assert.Equal(t, "john.doe@example.com", result.Email, "Expected the finder result to be kept")
+		assert.False(t, result.IsDeliverable())
+	})
+}
@@ -3,14 +3,16 @@ module github.com/Clustox/emailverifygo
 go 1.21
 
 require (
+	github.com/jarcoal/httpmock v1.4.1
 	github.com/joho/godotenv v1.5.1
+	github.com/stretchr/testify v1.11.1
+	golang.org/x/net v0.21.0
 	gopkg.in/guregu/null.v4 v4.0.0
 )
 
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
-	github.com/jarcoal/httpmock v1.4.1 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
-	github.com/stretchr/testify v1.11.1 // indirect
+	golang.org/x/text v0.14.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
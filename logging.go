@@ -0,0 +1,118 @@
+package emailverifygo
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// MAX_DEBUG_BODY_SIZE limits how much of a request or response body is dumped in debug mode
+const MAX_DEBUG_BODY_SIZE = 4096
+
+var (
+	// logger receives structured logs for every API call. Nil disables logging.
+	logger *slog.Logger
+
+	// debugDumps adds redacted request and response bodies to the debug logs
+	debugDumps bool
+)
+
+// SetLogger sets the slog.Logger used to log API calls and batch task transitions.
+// Pass nil to disable logging, which is the default.
+func SetLogger(newLogger *slog.Logger) {
+	logger = newLogger
+}
+
+// SetDebug turns request and response body dumps on or off. Dumps are logged at
+// debug level with the API key redacted, so the logger's level must allow them.
+func SetDebug(enabled bool) {
+	debugDumps = enabled
+}
+
+// attemptContextKey carries the attempt number of a request the package retries
+type attemptContextKey struct{}
+
+// withAttempt marks the requests made with ctx as the given attempt, counting from 1
+func withAttempt(ctx context.Context, attempt int) context.Context {
+	return context.WithValue(ctx, attemptContextKey{}, attempt)
+}
+
+// requestAttempt returns the attempt number of a request, 1 unless it is a retry
+func requestAttempt(ctx context.Context) int {
+	if attempt, ok := ctx.Value(attemptContextKey{}).(int); ok && attempt > 0 {
+		return attempt
+	}
+	return 1
+}
+
+// logRequest records the outcome of one API call
+func logRequest(req *http.Request, statusCode int, latency time.Duration, err error) {
+	if logger == nil {
+		return
+	}
+
+	attrs := []slog.Attr{
+		slog.String("method", req.Method),
+		slog.String("endpoint", req.URL.Path),
+		slog.Int("status_code", statusCode),
+		slog.Int("attempt", requestAttempt(req.Context())),
+		slog.Duration("latency", latency),
+	}
+	if err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+		logger.LogAttrs(req.Context(), slog.LevelWarn, "emailverify request failed", attrs...)
+		return
+	}
+	logger.LogAttrs(req.Context(), slog.LevelInfo, "emailverify request", attrs...)
+}
+
+// dumpRequest logs the redacted URL and body of a request in debug mode
+func dumpRequest(req *http.Request) {
+	if logger == nil || !debugDumps {
+		return
+	}
+
+	attrs := []slog.Attr{
+		slog.String("method", req.Method),
+		slog.String("url", RedactAPIKey(req.URL.String())),
+	}
+	if req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			data, _ := io.ReadAll(io.LimitReader(body, MAX_DEBUG_BODY_SIZE))
+			body.Close()
+			attrs = append(attrs, slog.String("body", RedactAPIKey(string(data))))
+		}
+	}
+	logger.LogAttrs(req.Context(), slog.LevelDebug, "emailverify request dump", attrs...)
+}
+
+// dumpResponse logs the redacted body of a response in debug mode
+func dumpResponse(req *http.Request, statusCode int, body []byte) {
+	if logger == nil || !debugDumps {
+		return
+	}
+
+	if len(body) > MAX_DEBUG_BODY_SIZE {
+		body = body[:MAX_DEBUG_BODY_SIZE]
+	}
+	logger.LogAttrs(req.Context(), slog.LevelDebug, "emailverify response dump",
+		slog.String("endpoint", req.URL.Path),
+		slog.Int("status_code", statusCode),
+		slog.String("body", RedactAPIKey(string(body))),
+	)
+}
+
+// logBatchTransition records a batch task moving from one status to another
+func logBatchTransition(taskID int, from, to string) {
+	if logger == nil {
+		return
+	}
+
+	logger.LogAttrs(context.Background(), slog.LevelInfo, "emailverify batch status changed",
+		slog.Int("task_id", taskID),
+		slog.String("from", from),
+		slog.String("to", to),
+	)
+}
@@ -0,0 +1,80 @@
+package emailverifygo
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogging(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	SetApiKey("test_api_key")
+
+	var output bytes.Buffer
+	SetLogger(slog.New(slog.NewTextHandler(&output, &slog.HandlerOptions{Level: slog.LevelDebug})))
+	defer SetLogger(nil)
+
+	httpmock.RegisterResponder("GET", `=~^(.*)`+ENDPOINT_VALIDATE+`(.*)\z`,
+		httpmock.NewStringResponder(200, MOCK_VALID_RESPONSE))
+	httpmock.RegisterResponder("GET", `=~^(.*)`+ENDPOINT_EMAIL_FINDER+`(.*)\z`,
+		httpmock.NewStringResponder(401, MOCK_ERROR_RESPONSE))
+	httpmock.RegisterResponder("POST", `=~^(.*)`+ENDPOINT_VALIDATE_BATCH+`(.*)\z`,
+		httpmock.NewStringResponder(200, MOCK_BATCH_RESPONSE))
+	httpmock.RegisterResponder("GET", `=~^(.*)`+ENDPOINT_BATCH_RESULT+`(.*)\z`,
+		httpmock.NewStringResponder(200, MOCK_BATCH_RESULTS_RESPONSE))
+
+	t.Run("TestRequestLogs", func(t *testing.T) {
+		output.Reset()
+		Validate("valid@example.com")
+		FindEmail("John Doe", "example.com")
+
+		logs := output.String()
+		assert.Contains(t, logs, "endpoint="+ENDPOINT_VALIDATE, "Expected the endpoint to be logged")
+		assert.Contains(t, logs, "status_code=200", "Expected the status code to be logged")
+		assert.Contains(t, logs, "latency=", "Expected the latency to be logged")
+		assert.Contains(t, logs, "attempt=1", "Expected first attempts to be numbered 1")
+		assert.Contains(t, logs, "level=WARN msg=\"emailverify request failed\"", "Expected failures at warn level")
+		assert.NotContains(t, logs, "dump", "Expected no dumps outside debug mode")
+	})
+
+	t.Run("TestRetryAttempts", func(t *testing.T) {
+		output.Reset()
+		GetBatchResultsContext(withAttempt(context.Background(), 3), 12345)
+
+		assert.Contains(t, output.String(), "attempt=3", "Expected a retry to log its attempt number")
+	})
+
+	t.Run("TestDebugDumps", func(t *testing.T) {
+		SetDebug(true)
+		defer SetDebug(false)
+		output.Reset()
+
+		result, err := ValidateBatch("Test Batch", []string{"valid@example.com"})
+		assert.Nil(t, err, "Expected the dump not to consume the response")
+		assert.Equal(t, 12345, result.TaskID, "Expected the response to decode after the dump")
+
+		logs := output.String()
+		assert.Contains(t, logs, "emailverify request dump", "Expected the request to be dumped")
+		assert.Contains(t, logs, "emailverify response dump", "Expected the response to be dumped")
+		assert.Contains(t, logs, "valid@example.com", "Expected the request body in the dump")
+		assert.NotContains(t, logs, "test_api_key", "Expected the key to be redacted from dumps")
+	})
+
+	t.Run("TestBatchTransitions", func(t *testing.T) {
+		output.Reset()
+		store := NewMemoryBatchJobStore()
+		job, _, _ := SubmitBatchJob(store, "Test Batch", []string{"valid@example.com"})
+		PollBatchJob(context.Background(), store, job, time.Millisecond)
+
+		logs := output.String()
+		assert.Contains(t, logs, "task_id=12345 from=\"\" to=submitted", "Expected the submission to be logged")
+		assert.Contains(t, logs, "task_id=12345 from=submitted to=verified", "Expected the verification to be logged")
+	})
+}
@@ -0,0 +1,331 @@
+package emailverifygo
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Metrics receives usage measurements from the request path and batch polling.
+// Implementations must be safe for concurrent use.
+type Metrics interface {
+	// ObserveRequest is called once per HTTP call. The status code is 0 when no
+	// response was received, and errorType is one of the ERROR_TYPE_* labels or
+	// empty on success.
+	ObserveRequest(endpoint string, statusCode int, latency time.Duration, errorType string)
+	// ObserveCache is called for every ValidateWithCache lookup
+	ObserveCache(hit bool)
+	// ObserveCredits is called with the credits a successful call is expected to consume
+	ObserveCredits(endpoint string, credits int)
+	// ObserveBatchPoll is called each time a batch poller fetches a task's status
+	ObserveBatchPoll(taskID int, status string)
+}
+
+// metrics receives measurements for every API call. Nil disables metrics.
+var metrics Metrics
+
+// SetMetrics sets the Metrics implementation. Pass nil to disable metrics, which is the default.
+func SetMetrics(newMetrics Metrics) {
+	metrics = newMetrics
+}
+
+func observeCache(hit bool) {
+	if metrics != nil {
+		metrics.ObserveCache(hit)
+	}
+}
+
+func observeCredits(endpoint string, credits int) {
+	if metrics != nil && credits > 0 {
+		metrics.ObserveCredits(endpoint, credits)
+	}
+}
+
+func observeBatchPoll(taskID int, status string) {
+	if metrics != nil {
+		metrics.ObserveBatchPoll(taskID, status)
+	}
+}
+
+// requestKey identifies one counter series of requests
+type requestKey struct {
+	endpoint string
+	label    string
+}
+
+// MemoryMetrics records measurements in memory. It is meant for tests.
+type MemoryMetrics struct {
+	mu          sync.Mutex
+	requests    map[requestKey]int
+	errors      map[requestKey]int
+	latencies   map[string][]time.Duration
+	credits     map[string]int
+	batchPolls  map[string]int
+	cacheHits   int
+	cacheMisses int
+}
+
+// NewMemoryMetrics creates an empty in-memory recorder
+func NewMemoryMetrics() *MemoryMetrics {
+	return &MemoryMetrics{
+		requests:   make(map[requestKey]int),
+		errors:     make(map[requestKey]int),
+		latencies:  make(map[string][]time.Duration),
+		credits:    make(map[string]int),
+		batchPolls: make(map[string]int),
+	}
+}
+
+func (m *MemoryMetrics) ObserveRequest(endpoint string, statusCode int, latency time.Duration, errorType string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.requests[requestKey{endpoint, strconv.Itoa(statusCode)}]++
+	m.latencies[endpoint] = append(m.latencies[endpoint], latency)
+	if errorType != "" {
+		m.errors[requestKey{endpoint, errorType}]++
+	}
+}
+
+func (m *MemoryMetrics) ObserveCache(hit bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if hit {
+		m.cacheHits++
+	} else {
+		m.cacheMisses++
+	}
+}
+
+func (m *MemoryMetrics) ObserveCredits(endpoint string, credits int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.credits[endpoint] += credits
+}
+
+func (m *MemoryMetrics) ObserveBatchPoll(taskID int, status string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.batchPolls[status]++
+}
+
+// Requests returns how many calls to the endpoint were made, whatever their outcome
+func (m *MemoryMetrics) Requests(endpoint string) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.latencies[endpoint])
+}
+
+// Errors returns how many calls to the endpoint failed with the given error type
+func (m *MemoryMetrics) Errors(endpoint, errorType string) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.errors[requestKey{endpoint, errorType}]
+}
+
+// Latencies returns the recorded latencies of calls to the endpoint
+func (m *MemoryMetrics) Latencies(endpoint string) []time.Duration {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]time.Duration(nil), m.latencies[endpoint]...)
+}
+
+// Credits returns the credits consumed through the endpoint
+func (m *MemoryMetrics) Credits(endpoint string) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.credits[endpoint]
+}
+
+// CacheHitRatio returns the share of cache lookups that were hits, 0 if there were none
+func (m *MemoryMetrics) CacheHitRatio() float64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.cacheHits+m.cacheMisses == 0 {
+		return 0
+	}
+	return float64(m.cacheHits) / float64(m.cacheHits+m.cacheMisses)
+}
+
+// BatchPolls returns how many polls returned the given task status
+func (m *MemoryMetrics) BatchPolls(status string) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.batchPolls[status]
+}
+
+// DEFAULT_LATENCY_BUCKETS are the histogram upper bounds, in seconds, used by NewPrometheusMetrics
+var DEFAULT_LATENCY_BUCKETS = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+type histogram struct {
+	counts []int // One count per bucket, not cumulative
+	sum    float64
+	count  int
+}
+
+// PrometheusMetrics aggregates measurements and serves them in the Prometheus
+// text exposition format. Mount it as the scrape handler, e.g. on /metrics.
+// It has no dependency on the Prometheus client library.
+type PrometheusMetrics struct {
+	mu          sync.Mutex
+	buckets     []float64
+	requests    map[requestKey]int
+	errors      map[requestKey]int
+	latencies   map[string]*histogram
+	credits     map[string]int
+	batchPolls  map[string]int
+	cacheHits   int
+	cacheMisses int
+}
+
+// NewPrometheusMetrics creates a collector with the given latency buckets in seconds.
+// With no buckets DEFAULT_LATENCY_BUCKETS are used.
+func NewPrometheusMetrics(buckets ...float64) *PrometheusMetrics {
+	if len(buckets) == 0 {
+		buckets = DEFAULT_LATENCY_BUCKETS
+	}
+	buckets = append([]float64(nil), buckets...)
+	sort.Float64s(buckets)
+
+	return &PrometheusMetrics{
+		buckets:    buckets,
+		requests:   make(map[requestKey]int),
+		errors:     make(map[requestKey]int),
+		latencies:  make(map[string]*histogram),
+		credits:    make(map[string]int),
+		batchPolls: make(map[string]int),
+	}
+}
+
+func (p *PrometheusMetrics) ObserveRequest(endpoint string, statusCode int, latency time.Duration, errorType string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.requests[requestKey{endpoint, strconv.Itoa(statusCode)}]++
+	if errorType != "" {
+		p.errors[requestKey{endpoint, errorType}]++
+	}
+
+	h, ok := p.latencies[endpoint]
+	if !ok {
+		h = &histogram{counts: make([]int, len(p.buckets))}
+		p.latencies[endpoint] = h
+	}
+	seconds := latency.Seconds()
+	for i, bound := range p.buckets {
+		if seconds <= bound {
+			h.counts[i]++
+			break
+		}
+	}
+	h.sum += seconds
+	h.count++
+}
+
+func (p *PrometheusMetrics) ObserveCache(hit bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if hit {
+		p.cacheHits++
+	} else {
+		p.cacheMisses++
+	}
+}
+
+func (p *PrometheusMetrics) ObserveCredits(endpoint string, credits int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.credits[endpoint] += credits
+}
+
+func (p *PrometheusMetrics) ObserveBatchPoll(taskID int, status string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.batchPolls[status]++
+}
+
+// ServeHTTP writes the current metrics in the Prometheus text format
+func (p *PrometheusMetrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	p.WriteTo(w)
+}
+
+// WriteTo writes the current metrics in the Prometheus text format
+func (p *PrometheusMetrics) WriteTo(w io.Writer) (int64, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var b strings.Builder
+
+	writeHeader(&b, "emailverify_requests_total", "counter", "API requests by endpoint and HTTP status code (0 when no response was received).")
+	for _, key := range sortedRequestKeys(p.requests) {
+		fmt.Fprintf(&b, "emailverify_requests_total{endpoint=%q,code=%q} %d\n", key.endpoint, key.label, p.requests[key])
+	}
+
+	writeHeader(&b, "emailverify_request_errors_total", "counter", "Failed API requests by endpoint and error type.")
+	for _, key := range sortedRequestKeys(p.errors) {
+		fmt.Fprintf(&b, "emailverify_request_errors_total{endpoint=%q,type=%q} %d\n", key.endpoint, key.label, p.errors[key])
+	}
+
+	writeHeader(&b, "emailverify_request_duration_seconds", "histogram", "API request latency by endpoint.")
+	for _, endpoint := range sortedKeys(p.latencies) {
+		h := p.latencies[endpoint]
+		cumulative := 0
+		for i, bound := range p.buckets {
+			cumulative += h.counts[i]
+			fmt.Fprintf(&b, "emailverify_request_duration_seconds_bucket{endpoint=%q,le=%q} %d\n",
+				endpoint, strconv.FormatFloat(bound, 'g', -1, 64), cumulative)
+		}
+		fmt.Fprintf(&b, "emailverify_request_duration_seconds_bucket{endpoint=%q,le=\"+Inf\"} %d\n", endpoint, h.count)
+		fmt.Fprintf(&b, "emailverify_request_duration_seconds_sum{endpoint=%q} %g\n", endpoint, h.sum)
+		fmt.Fprintf(&b, "emailverify_request_duration_seconds_count{endpoint=%q} %d\n", endpoint, h.count)
+	}
+
+	writeHeader(&b, "emailverify_cache_lookups_total", "counter", "Validation cache lookups by result.")
+	fmt.Fprintf(&b, "emailverify_cache_lookups_total{result=\"hit\"} %d\n", p.cacheHits)
+	fmt.Fprintf(&b, "emailverify_cache_lookups_total{result=\"miss\"} %d\n", p.cacheMisses)
+
+	writeHeader(&b, "emailverify_credits_consumed_total", "counter", "Credits expected to be consumed by endpoint.")
+	for _, endpoint := range sortedKeys(p.credits) {
+		fmt.Fprintf(&b, "emailverify_credits_consumed_total{endpoint=%q} %d\n", endpoint, p.credits[endpoint])
+	}
+
+	writeHeader(&b, "emailverify_batch_polls_total", "counter", "Batch task polls by returned status.")
+	for _, status := range sortedKeys(p.batchPolls) {
+		fmt.Fprintf(&b, "emailverify_batch_polls_total{status=%q} %d\n", status, p.batchPolls[status])
+	}
+
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+func writeHeader(b *strings.Builder, name, kind, help string) {
+	fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+func sortedRequestKeys(m map[requestKey]int) []requestKey {
+	keys := make([]requestKey, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].endpoint != keys[j].endpoint {
+			return keys[i].endpoint < keys[j].endpoint
+		}
+		return keys[i].label < keys[j].label
+	})
+	return keys
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
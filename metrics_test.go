@@ -0,0 +1,79 @@
+package emailverifygo
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetrics(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	SetApiKey("test_api_key")
+
+	httpmock.RegisterResponder("GET", `=~^(.*)`+ENDPOINT_VALIDATE+`(.*)\z`,
+		httpmock.NewStringResponder(200, MOCK_VALID_RESPONSE))
+	httpmock.RegisterResponder("GET", `=~^(.*)`+ENDPOINT_EMAIL_FINDER+`(.*)\z`,
+		httpmock.NewStringResponder(401, MOCK_ERROR_RESPONSE))
+	httpmock.RegisterResponder("GET", `=~^(.*)`+ENDPOINT_ACCOUNT_BALANCE+`(.*)\z`,
+		httpmock.NewErrorResponder(errors.New("connection refused")))
+	httpmock.RegisterResponder("POST", `=~^(.*)`+ENDPOINT_VALIDATE_BATCH+`(.*)\z`,
+		httpmock.NewStringResponder(200, MOCK_BATCH_RESPONSE))
+	httpmock.RegisterResponder("GET", `=~^(.*)`+ENDPOINT_BATCH_RESULT+`(.*)\z`,
+		httpmock.NewStringResponder(200, MOCK_BATCH_RESULTS_RESPONSE))
+
+	run := func() {
+		cache := NewMemoryValidationCache(0)
+		ValidateWithCache(cache, "valid@example.com")
+		ValidateWithCache(cache, "valid@example.com")
+		FindEmail("John Doe", "example.com")
+		GetAccountBalance()
+
+		store := NewMemoryBatchJobStore()
+		job, _, _ := SubmitBatchJob(store, "Test Batch", []string{"a@example.com", "b@example.com", "c@example.com"})
+		PollBatchJob(context.Background(), store, job, time.Millisecond)
+	}
+
+	t.Run("TestMemoryMetrics", func(t *testing.T) {
+		recorder := NewMemoryMetrics()
+		SetMetrics(recorder)
+		defer SetMetrics(nil)
+		run()
+
+		assert.Equal(t, 1, recorder.Requests(ENDPOINT_VALIDATE), "Expected the cached lookup not to reach the API")
+		assert.Equal(t, 1, len(recorder.Latencies(ENDPOINT_VALIDATE)), "Expected one latency sample")
+		assert.Equal(t, 0.5, recorder.CacheHitRatio(), "Expected one hit and one miss")
+		assert.Equal(t, 1, recorder.Errors(ENDPOINT_EMAIL_FINDER, ERROR_TYPE_UNAUTHORIZED), "Expected the 401 to be classified")
+		assert.Equal(t, 1, recorder.Errors(ENDPOINT_ACCOUNT_BALANCE, ERROR_TYPE_TRANSPORT), "Expected the transport failure to be classified")
+		assert.Equal(t, 1, recorder.Credits(ENDPOINT_VALIDATE), "Expected one validation credit")
+		assert.Equal(t, 0, recorder.Credits(ENDPOINT_EMAIL_FINDER), "Expected no credits for a failed lookup")
+		assert.Equal(t, 3, recorder.Credits(ENDPOINT_VALIDATE_BATCH), "Expected one credit per processed batch address")
+		assert.Equal(t, 1, recorder.BatchPolls(BATCH_STATUS_VERIFIED), "Expected the poll to be counted")
+	})
+
+	t.Run("TestPrometheusMetrics", func(t *testing.T) {
+		collector := NewPrometheusMetrics()
+		SetMetrics(collector)
+		defer SetMetrics(nil)
+		run()
+
+		recorder := httptest.NewRecorder()
+		collector.ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+		body := recorder.Body.String()
+
+		assert.Contains(t, body, "# TYPE emailverify_requests_total counter", "Expected type metadata")
+		assert.Contains(t, body, `emailverify_requests_total{endpoint="/api/v1/validate",code="200"} 1`, "Expected request counts")
+		assert.Contains(t, body, `emailverify_requests_total{endpoint="/api/v1/check-account-balance",code="0"} 1`, "Expected transport failures with code 0")
+		assert.Contains(t, body, `emailverify_request_errors_total{endpoint="/api/v1/finder",type="unauthorized"} 1`, "Expected error counts by type")
+		assert.Contains(t, body, `emailverify_request_duration_seconds_bucket{endpoint="/api/v1/validate",le="+Inf"} 1`, "Expected latency histograms")
+		assert.Contains(t, body, `emailverify_cache_lookups_total{result="hit"} 1`, "Expected cache hits")
+		assert.Contains(t, body, `emailverify_credits_consumed_total{endpoint="/api/v1/validate-batch"} 3`, "Expected credits by endpoint")
+		assert.Contains(t, body, `emailverify_batch_polls_total{status="verified"} 1`, "Expected batch polls by status")
+	})
+}
@@ -0,0 +1,330 @@
+package emailverifygo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// Default settings used by NewMicroBatcher when an option is left at zero
+const (
+	DEFAULT_MICROBATCH_WINDOW             = 2 * time.Second
+	DEFAULT_MICROBATCH_MIN_SIZE           = 10
+	DEFAULT_MICROBATCH_MAX_SIZE           = 1000
+	DEFAULT_MICROBATCH_POLL_INTERVAL      = 5 * time.Second
+	DEFAULT_MICROBATCH_SINGLE_CONCURRENCY = 5
+	DEFAULT_MICROBATCH_TITLE              = "emailverifygo micro-batch"
+)
+
+// ErrMicroBatcherClosed is returned for validations submitted after Close
+var ErrMicroBatcherClosed = errors.New("micro-batcher is closed")
+
+// ValidationFuture is the pending result of a validation submitted to a MicroBatcher
+type ValidationFuture struct {
+	done     chan struct{}
+	response *ValidateResponse
+	err      error
+}
+
+func newValidationFuture() *ValidationFuture {
+	return &ValidationFuture{done: make(chan struct{})}
+}
+
+func (f *ValidationFuture) resolve(response *ValidateResponse, err error) {
+	f.response = response
+	f.err = err
+	close(f.done)
+}
+
+// Done is closed once the result is available
+func (f *ValidationFuture) Done() <-chan struct{} {
+	return f.done
+}
+
+// Wait blocks until the result is available or ctx is done. The validation
+// carries on in the background if ctx is done first.
+func (f *ValidationFuture) Wait(ctx context.Context) (*ValidateResponse, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-f.done:
+		return f.response, f.err
+	}
+}
+
+// MicroBatcherOptions configures a MicroBatcher
+type MicroBatcherOptions struct {
+	// Window is how long submissions are collected before they are sent
+	Window time.Duration
+	// MinBatchSize is the fewest distinct addresses worth a batch task. Smaller
+	// groups, as under low load, are validated with single calls.
+	MinBatchSize int
+	// MaxBatchSize sends the collected addresses at once when reached
+	MaxBatchSize int
+	// PollInterval is how often a submitted batch task is polled for results
+	PollInterval time.Duration
+	// LatencySLO is the longest a caller should wait for a result. A group that
+	// could not be polled within it is validated with single calls, and a batch
+	// task not finished by then is abandoned for single calls, spending credits
+	// twice. Zero waits for batch tasks however long they take.
+	LatencySLO time.Duration
+	// SingleConcurrency is how many single calls run at once
+	SingleConcurrency int
+	// Title names the batch tasks
+	Title string
+}
+
+type pendingValidation struct {
+	address  string
+	future   *ValidationFuture
+	queuedAt time.Time
+}
+
+// MicroBatcher collects single validations for a short window and submits them
+// together with ValidateBatch, resolving each caller's future when the batch
+// results arrive. It is meant for high-volume paths that would otherwise call
+// Validate once per address.
+type MicroBatcher struct {
+	opts MicroBatcherOptions
+
+	mu      sync.Mutex
+	pending []pendingValidation
+	timer   *time.Timer
+	closed  bool
+	running sync.WaitGroup
+}
+
+// NewMicroBatcher creates a micro-batcher. Call Close to flush it when done.
+func NewMicroBatcher(opts MicroBatcherOptions) *MicroBatcher {
+	if opts.Window <= 0 {
+		opts.Window = DEFAULT_MICROBATCH_WINDOW
+	}
+	if opts.MinBatchSize <= 0 {
+		opts.MinBatchSize = DEFAULT_MICROBATCH_MIN_SIZE
+	}
+	if opts.MaxBatchSize <= 0 {
+		opts.MaxBatchSize = DEFAULT_MICROBATCH_MAX_SIZE
+	}
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = DEFAULT_MICROBATCH_POLL_INTERVAL
+	}
+	if opts.SingleConcurrency <= 0 {
+		opts.SingleConcurrency = DEFAULT_MICROBATCH_SINGLE_CONCURRENCY
+	}
+	if opts.Title == "" {
+		opts.Title = DEFAULT_MICROBATCH_TITLE
+	}
+	return &MicroBatcher{opts: opts}
+}
+
+// Submit queues an address for validation and returns its future
+func (m *MicroBatcher) Submit(email string) *ValidationFuture {
+	future := newValidationFuture()
+	if email == "" {
+		future.resolve(nil, fmt.Errorf("email cannot be empty"))
+		return future
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		future.resolve(nil, ErrMicroBatcherClosed)
+		return future
+	}
+
+	m.pending = append(m.pending, pendingValidation{address: NormalizeEmail(email), future: future, queuedAt: time.Now()})
+	if len(m.pending) >= m.opts.MaxBatchSize {
+		m.flushLocked()
+	} else if m.timer == nil {
+		m.timer = time.AfterFunc(m.opts.Window, m.flush)
+	}
+	return future
+}
+
+// Validate submits an address and waits for its result or for ctx to be done
+func (m *MicroBatcher) Validate(ctx context.Context, email string) (*ValidateResponse, error) {
+	return m.Submit(email).Wait(ctx)
+}
+
+// Close sends any collected addresses and waits until every submitted
+// validation is resolved. Later submissions fail with ErrMicroBatcherClosed.
+func (m *MicroBatcher) Close() {
+	m.mu.Lock()
+	m.closed = true
+	m.flushLocked()
+	m.mu.Unlock()
+
+	m.running.Wait()
+}
+
+func (m *MicroBatcher) flush() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.flushLocked()
+}
+
+// flushLocked hands the collected addresses to a background worker. Callers must hold m.mu.
+func (m *MicroBatcher) flushLocked() {
+	if m.timer != nil {
+		m.timer.Stop()
+		m.timer = nil
+	}
+	if len(m.pending) == 0 {
+		return
+	}
+
+	group := m.pending
+	m.pending = nil
+	m.running.Add(1)
+	go func() {
+		defer m.running.Done()
+		m.process(group)
+	}()
+}
+
+// process validates one group of collected addresses, by batch or by single calls
+func (m *MicroBatcher) process(group []pendingValidation) {
+	var order []string
+	waiters := make(map[string][]*ValidationFuture)
+	for _, pending := range group {
+		if _, seen := waiters[pending.address]; !seen {
+			order = append(order, pending.address)
+		}
+		waiters[pending.address] = append(waiters[pending.address], pending.future)
+	}
+
+	ctx := context.Background()
+	if m.opts.LatencySLO > 0 {
+		deadline := group[0].queuedAt.Add(m.opts.LatencySLO)
+		if time.Until(deadline) < m.opts.PollInterval {
+			m.validateSingly(order, waiters)
+			return
+		}
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithDeadline(ctx, deadline)
+		defer cancel()
+	}
+	if len(order) < m.opts.MinBatchSize {
+		m.validateSingly(order, waiters)
+		return
+	}
+
+	submission, err := ValidateBatchContext(ctx, m.opts.Title, order)
+	if err != nil {
+		// The failed request is already logged. Fall back to single calls only when
+		// the batch surely spent nothing and single calls may fare better; a refused
+		// key or throttling would only multiply, and a late answer may mean the
+		// batch was accepted.
+		if batchFallbackAllowed(err) {
+			m.validateSingly(order, waiters)
+			return
+		}
+		for _, address := range order {
+			for _, future := range waiters[address] {
+				future.resolve(nil, err)
+			}
+		}
+		return
+	}
+	for _, rejection := range submission.Errors {
+		address := NormalizeEmail(rejection.Address)
+		for _, future := range waiters[address] {
+			future.resolve(nil, fmt.Errorf("rejected by batch validation: %s", rejection.Error))
+		}
+		delete(waiters, address)
+	}
+
+	results := m.pollBatch(ctx, submission.TaskID)
+	if results != nil {
+		for _, result := range results.Results.EmailBatch {
+			address := NormalizeEmail(result.Address)
+			for _, future := range waiters[address] {
+				future.resolve(&ValidateResponse{Email: result.Address, Status: result.Status, SubStatus: result.SubStatus, Provider: result.Provider}, nil)
+			}
+			delete(waiters, address)
+		}
+	}
+
+	// Anything the batch did not answer in time is validated singly
+	var leftover []string
+	for _, address := range order {
+		if _, ok := waiters[address]; ok {
+			leftover = append(leftover, address)
+		}
+	}
+	m.validateSingly(leftover, waiters)
+}
+
+// batchFallbackAllowed reports whether a failed batch submission may be retried
+// with single calls: the request never got a response, or the API failed with a 5xx
+func batchFallbackAllowed(err error) bool {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode >= 500
+	}
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+		return false
+	}
+	var urlErr *url.Error
+	return errors.As(err, &urlErr)
+}
+
+// pollBatch polls a task until it is verified, returning nil if ctx is done
+// first or polling keeps failing
+func (m *MicroBatcher) pollBatch(ctx context.Context, taskID int) *BatchResultResponse {
+	ticker := time.NewTicker(m.opts.PollInterval)
+	defer ticker.Stop()
+
+	failures := 0
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		results, err := GetBatchResultsContext(withAttempt(ctx, failures+1), taskID)
+		if err != nil {
+			failures++
+			if failures >= DEFAULT_BATCH_MAX_POLL_FAILURES {
+				return nil
+			}
+			continue
+		}
+		failures = 0
+		observeBatchPoll(taskID, results.Status)
+		if results.IsComplete() {
+			return results
+		}
+	}
+}
+
+// validateSingly validates each address with its own call and resolves its waiters
+func (m *MicroBatcher) validateSingly(addresses []string, waiters map[string][]*ValidationFuture) {
+	work := make(chan string)
+	var workers sync.WaitGroup
+	for i := 0; i < m.opts.SingleConcurrency && i < len(addresses); i++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for address := range work {
+				response, err := ValidateContext(context.Background(), address)
+				for j, future := range waiters[address] {
+					if j > 0 && response != nil {
+						copied := *response
+						response = &copied
+					}
+					future.resolve(response, err)
+				}
+			}
+		}()
+	}
+	for _, address := range addresses {
+		work <- address
+	}
+	close(work)
+	workers.Wait()
+}
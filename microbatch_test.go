@@ -0,0 +1,189 @@
+package emailverifygo
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMicroBatcher(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	SetApiKey("test_api_key")
+
+	var batchComplete atomic.Bool
+	httpmock.RegisterResponder("GET", `=~^(.*)`+ENDPOINT_VALIDATE+`(.*)\z`,
+		func(r *http.Request) (*http.Response, error) {
+			email := r.URL.Query().Get("email")
+			return httpmock.NewStringResponse(200, fmt.Sprintf(`{"email": %q, "status": "valid", "sub_status": ""}`, email)), nil
+		},
+	)
+	httpmock.RegisterResponder("POST", `=~^(.*)`+ENDPOINT_VALIDATE_BATCH+`(.*)\z`,
+		httpmock.NewStringResponder(200, `{"status": "success", "task_id": 777, "count_submitted": 3, "count_rejected_emails": 1,
+			"errors": [{"address": "bad@", "error": "Invalid email address"}]}`))
+	httpmock.RegisterResponder("GET", `=~^(.*)`+ENDPOINT_BATCH_RESULT+`(.*)\z`,
+		func(r *http.Request) (*http.Response, error) {
+			if !batchComplete.Load() {
+				return httpmock.NewStringResponse(200, MOCK_BATCH_IN_PROGRESS_RESPONSE), nil
+			}
+			return httpmock.NewStringResponse(200, `{"status": "verified", "task_id": 777, "count_checked": 2, "count_total": 2,
+				"results": {"email_batch": [
+					{"address": "one@example.com", "status": "valid", "sub_status": ""},
+					{"address": "two@example.com", "status": "invalid", "sub_status": "mailbox_not_found"}
+				]}}`), nil
+		},
+	)
+
+	ctx := context.Background()
+
+	t.Run("TestBatchesUnderLoad", func(t *testing.T) {
+		httpmock.ZeroCallCounters()
+		batchComplete.Store(true)
+		batcher := NewMicroBatcher(MicroBatcherOptions{Window: 20 * time.Millisecond, MinBatchSize: 2, PollInterval: 5 * time.Millisecond})
+
+		one := batcher.Submit("one@example.com")
+		oneAgain := batcher.Submit(" ONE@example.com")
+		two := batcher.Submit("two@example.com")
+		bad := batcher.Submit("bad@")
+
+		response, err := one.Wait(ctx)
+		assert.Nil(t, err, "Expected no error")
+		assert.Equal(t, STATUS_VALID, response.Status)
+
+		response, err = oneAgain.Wait(ctx)
+		assert.Nil(t, err, "Expected duplicates to share the result")
+		assert.Equal(t, STATUS_VALID, response.Status)
+
+		response, err = two.Wait(ctx)
+		assert.Nil(t, err)
+		assert.Equal(t, SUBSTATUS_MAILBOX_NOT_FOUND, response.SubStatus)
+
+		_, err = bad.Wait(ctx)
+		assert.ErrorContains(t, err, "Invalid email address", "Expected the batch rejection to reach the caller")
+
+		batcher.Close()
+		calls := httpmock.GetCallCountInfo()
+		assert.Equal(t, 1, calls["POST =~^(.*)"+ENDPOINT_VALIDATE_BATCH+`(.*)\z`], "Expected a single batch submission")
+		assert.Equal(t, 0, calls["GET =~^(.*)"+ENDPOINT_VALIDATE+`(.*)\z`], "Expected no single calls")
+	})
+
+	t.Run("TestBatchAnswersCarryProvider", func(t *testing.T) {
+		defer SetProviderDetection(nil)
+		SetProviderDetection(NewDomainChecker(DomainCheckerOptions{Resolver: providerResolver()}))
+		batchComplete.Store(true)
+		batcher := NewMicroBatcher(MicroBatcherOptions{Window: 20 * time.Millisecond, MinBatchSize: 2, PollInterval: 5 * time.Millisecond})
+		defer batcher.Close()
+
+		one := batcher.Submit("one@example.com")
+		batcher.Submit("two@example.com")
+
+		response, err := one.Wait(ctx)
+		assert.Nil(t, err, "Expected no error")
+		if assert.NotNil(t, response.Provider, "Expected the batch result's provider on the answer") {
+			assert.Equal(t, PROVIDER_GOOGLE, response.Provider.Provider)
+		}
+	})
+
+	t.Run("TestSingleCallsUnderLowLoad", func(t *testing.T) {
+		httpmock.ZeroCallCounters()
+		batcher := NewMicroBatcher(MicroBatcherOptions{Window: 5 * time.Millisecond, MinBatchSize: 10})
+
+		response, err := batcher.Validate(ctx, "lonely@example.com")
+		assert.Nil(t, err, "Expected no error")
+		assert.Equal(t, "lonely@example.com", response.Email)
+
+		batcher.Close()
+		calls := httpmock.GetCallCountInfo()
+		assert.Equal(t, 0, calls["POST =~^(.*)"+ENDPOINT_VALIDATE_BATCH+`(.*)\z`], "Expected no batch for a small group")
+		assert.Equal(t, 1, calls["GET =~^(.*)"+ENDPOINT_VALIDATE+`(.*)\z`])
+	})
+
+	t.Run("TestLatencySLOFallback", func(t *testing.T) {
+		httpmock.ZeroCallCounters()
+		batchComplete.Store(false)
+		batcher := NewMicroBatcher(MicroBatcherOptions{
+			Window:       5 * time.Millisecond,
+			MinBatchSize: 2,
+			PollInterval: 10 * time.Millisecond,
+			LatencySLO:   50 * time.Millisecond,
+		})
+
+		one := batcher.Submit("one@example.com")
+		two := batcher.Submit("two@example.com")
+
+		response, err := one.Wait(ctx)
+		assert.Nil(t, err, "Expected the single call fallback to answer")
+		assert.Equal(t, "one@example.com", response.Email)
+		_, err = two.Wait(ctx)
+		assert.Nil(t, err)
+
+		batcher.Close()
+		calls := httpmock.GetCallCountInfo()
+		assert.Equal(t, 1, calls["POST =~^(.*)"+ENDPOINT_VALIDATE_BATCH+`(.*)\z`], "Expected the batch to be tried first")
+		assert.Equal(t, 2, calls["GET =~^(.*)"+ENDPOINT_VALIDATE+`(.*)\z`], "Expected unfinished addresses to be validated singly")
+	})
+
+	t.Run("TestClosed", func(t *testing.T) {
+		batcher := NewMicroBatcher(MicroBatcherOptions{})
+		batcher.Close()
+
+		_, err := batcher.Validate(ctx, "late@example.com")
+		assert.ErrorIs(t, err, ErrMicroBatcherClosed)
+	})
+}
+
+func TestMicroBatcherSubmissionErrors(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	SetApiKey("test_api_key")
+
+	httpmock.RegisterResponder("GET", `=~^(.*)`+ENDPOINT_VALIDATE+`(.*)\z`,
+		func(r *http.Request) (*http.Response, error) {
+			email := r.URL.Query().Get("email")
+			return httpmock.NewStringResponse(200, fmt.Sprintf(`{"email": %q, "status": "valid", "sub_status": ""}`, email)), nil
+		},
+	)
+
+	ctx := context.Background()
+	for _, tc := range []struct {
+		name     string
+		batch    httpmock.Responder
+		fallBack bool
+	}{
+		{"TestServerErrorFallsBack", httpmock.NewStringResponder(500, MOCK_ERROR_RESPONSE), true},
+		{"TestTransportErrorFallsBack", httpmock.NewErrorResponder(fmt.Errorf("connection reset")), true},
+		{"TestUnauthorizedFails", httpmock.NewStringResponder(401, MOCK_ERROR_RESPONSE), false},
+		{"TestRateLimitedFails", httpmock.NewStringResponder(429, `{"error": "Too many requests"}`), false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			httpmock.ZeroCallCounters()
+			httpmock.RegisterResponder("POST", `=~^(.*)`+ENDPOINT_VALIDATE_BATCH+`(.*)\z`, tc.batch)
+			batcher := NewMicroBatcher(MicroBatcherOptions{Window: 5 * time.Millisecond, MinBatchSize: 2})
+
+			one := batcher.Submit("one@example.com")
+			two := batcher.Submit("two@example.com")
+			_, errOne := one.Wait(ctx)
+			_, errTwo := two.Wait(ctx)
+			batcher.Close()
+
+			singles := httpmock.GetCallCountInfo()["GET =~^(.*)"+ENDPOINT_VALIDATE+`(.*)\z`]
+			if tc.fallBack {
+				assert.Nil(t, errOne, "Expected the single call fallback to answer")
+				assert.Nil(t, errTwo)
+				assert.Equal(t, 2, singles)
+			} else {
+				assert.NotNil(t, errOne, "Expected the submission error to reach the caller")
+				assert.NotNil(t, errTwo)
+				assert.Equal(t, 0, singles, "Expected no single calls after a 4xx")
+			}
+		})
+	}
+}
@@ -0,0 +1,187 @@
+package emailverifygo
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+)
+
+// MAX_MIDDLEWARE_BODY_SIZE limits how much of a JSON body the middleware inspects
+const MAX_MIDDLEWARE_BODY_SIZE = 1 << 20
+
+// Errors set on RequestVerification.Err by the middleware
+var (
+	ErrEmailRequired = errors.New("email is required")
+	ErrEmailRejected = errors.New("email rejected")
+	ErrBadRequest    = errors.New("failed to read request body")
+)
+
+// EmailPolicy decides whether a validation result is acceptable.
+// It returns nil to accept the address, or an error explaining the rejection.
+type EmailPolicy func(response *ValidateResponse) error
+
+// DefaultEmailPolicy rejects invalid and do-not-mail addresses and accepts everything else
+func DefaultEmailPolicy(response *ValidateResponse) error {
+	switch response.Status {
+	case STATUS_INVALID, STATUS_DO_NOT_MAIL:
+		return fmt.Errorf("%w: %s (%s)", ErrEmailRejected, response.Status, response.SubStatus)
+	}
+	return nil
+}
+
+// StatusPolicy accepts only the given statuses
+func StatusPolicy(allowed ...string) EmailPolicy {
+	return func(response *ValidateResponse) error {
+		for _, status := range allowed {
+			if response.Status == status {
+				return nil
+			}
+		}
+		return fmt.Errorf("%w: %s (%s)", ErrEmailRejected, response.Status, response.SubStatus)
+	}
+}
+
+// RequestVerification is the outcome of the middleware for one request
+type RequestVerification struct {
+	Email    string            // The address found in the request
+	Response *ValidateResponse // The validation result, nil if the address never reached the API
+	Err      error             // Why the address was rejected or could not be validated, nil if accepted
+}
+
+// MiddlewareOptions configures ValidationMiddleware
+type MiddlewareOptions struct {
+	// Field is the form, JSON or query field holding the address. Defaults to "email".
+	Field string
+	// Required rejects requests without the field. Otherwise they pass through untouched.
+	Required bool
+	// Cache, if set, answers repeated addresses without another API call
+	Cache ValidationCache
+	// Policy decides which results are accepted. Defaults to DefaultEmailPolicy.
+	Policy EmailPolicy
+	// AnnotateOnly never rejects; the outcome is only stored in the request context
+	AnnotateOnly bool
+	// FailOpen lets requests through when the API itself fails, instead of rejecting them
+	FailOpen bool
+	// OnReject writes the response for rejected requests. Defaults to a JSON error body.
+	OnReject func(w http.ResponseWriter, r *http.Request, verification *RequestVerification)
+}
+
+type verificationContextKey struct{}
+
+// VerificationFromContext returns the middleware outcome stored in the request context
+func VerificationFromContext(ctx context.Context) (*RequestVerification, bool) {
+	verification, ok := ctx.Value(verificationContextKey{}).(*RequestVerification)
+	return verification, ok
+}
+
+// ValidationMiddleware returns net/http middleware that extracts an email field
+// from the JSON body, form or query string, checks it locally, validates it
+// through the cache and API, applies the policy, and then either rejects the
+// request or passes it on with the outcome in the request context.
+func ValidationMiddleware(opts MiddlewareOptions) func(http.Handler) http.Handler {
+	if opts.Field == "" {
+		opts.Field = "email"
+	}
+	if opts.Policy == nil {
+		opts.Policy = DefaultEmailPolicy
+	}
+	if opts.OnReject == nil {
+		opts.OnReject = writeRejection
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			email, err := extractEmailField(r, opts.Field)
+			if err != nil {
+				opts.OnReject(w, r, &RequestVerification{Err: err})
+				return
+			}
+			if email == "" && !opts.Required {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			verification, apiFailed := verifyRequestEmail(r.Context(), email, opts)
+			if verification.Err != nil && !opts.AnnotateOnly && !(apiFailed && opts.FailOpen) {
+				opts.OnReject(w, r, verification)
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), verificationContextKey{}, verification)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// verifyRequestEmail runs the checks for one address, carrying the inbound
+// request's ctx to the API call. It reports whether the failure, if any, came
+// from the API rather than from the address itself.
+func verifyRequestEmail(ctx context.Context, email string, opts MiddlewareOptions) (*RequestVerification, bool) {
+	verification := &RequestVerification{Email: email}
+	if email == "" {
+		verification.Err = ErrEmailRequired
+		return verification, false
+	}
+	if err := CheckEmailSyntax(email); err != nil {
+		verification.Err = fmt.Errorf("%w: %v", ErrEmailRejected, err)
+		return verification, false
+	}
+
+	response, err := ValidateWithCacheContext(ctx, opts.Cache, email)
+	if err != nil {
+		verification.Err = err
+		return verification, true
+	}
+	verification.Response = response
+	verification.Err = opts.Policy(response)
+	return verification, false
+}
+
+// extractEmailField reads the field from a JSON body, or from the form and query string.
+// A JSON body is put back so the downstream handler can read it again.
+func extractEmailField(r *http.Request, field string) (string, error) {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType == "application/json" && r.Body != nil {
+		// Only the first MAX_MIDDLEWARE_BODY_SIZE bytes are inspected. They are put back in
+		// front of the unread rest, so a larger body reaches the handler whole; its field
+		// does not parse from the prefix and is looked for in the query string instead.
+		body, err := io.ReadAll(io.LimitReader(r.Body, MAX_MIDDLEWARE_BODY_SIZE))
+		r.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(body), r.Body), r.Body}
+		if err != nil {
+			return "", fmt.Errorf("%w: %v", ErrBadRequest, err)
+		}
+
+		var payload map[string]any
+		if len(body) > 0 && json.Unmarshal(body, &payload) == nil {
+			if value, ok := payload[field].(string); ok {
+				return value, nil
+			}
+		}
+		return r.URL.Query().Get(field), nil
+	}
+	return r.FormValue(field), nil
+}
+
+func writeRejection(w http.ResponseWriter, r *http.Request, verification *RequestVerification) {
+	var status int
+	switch {
+	case errors.Is(verification.Err, ErrBadRequest):
+		status = http.StatusBadRequest
+	case errors.Is(verification.Err, ErrEmailRejected), errors.Is(verification.Err, ErrEmailRequired):
+		status = http.StatusUnprocessableEntity
+	default:
+		status = http.StatusServiceUnavailable
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": verification.Err.Error()})
+}
@@ -0,0 +1,135 @@
+package emailverifygo
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidationMiddleware(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	SetApiKey("test_api_key")
+
+	httpmock.RegisterResponder("GET", `=~^(.*)`+ENDPOINT_VALIDATE+`(.*)\z`,
+		func(r *http.Request) (*http.Response, error) {
+			switch r.URL.Query().Get("email") {
+			case "valid@example.com":
+				return httpmock.NewStringResponse(200, MOCK_VALID_RESPONSE), nil
+			case "down@example.com":
+				return httpmock.NewStringResponse(500, MOCK_ERROR_RESPONSE), nil
+			default:
+				return httpmock.NewStringResponse(200, MOCK_INVALID_RESPONSE), nil
+			}
+		},
+	)
+
+	// The downstream handler echoes the body it received and the verification status
+	downstream := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		status := "none"
+		if verification, ok := VerificationFromContext(r.Context()); ok && verification.Response != nil {
+			status = verification.Response.Status
+		}
+		w.Write([]byte(status + "|" + string(body)))
+	})
+
+	serve := func(opts MiddlewareOptions, r *http.Request) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		ValidationMiddleware(opts)(downstream).ServeHTTP(recorder, r)
+		return recorder
+	}
+
+	t.Run("TestJSONBodyAccepted", func(t *testing.T) {
+		r := httptest.NewRequest("POST", "/signup", strings.NewReader(`{"email": "valid@example.com"}`))
+		r.Header.Set("Content-Type", "application/json")
+		recorder := serve(MiddlewareOptions{}, r)
+
+		assert.Equal(t, 200, recorder.Code, "Expected valid email to pass")
+		assert.Equal(t, `valid|{"email": "valid@example.com"}`, recorder.Body.String(), "Expected body to be readable downstream")
+	})
+
+	t.Run("TestLargeJSONBodyKeptWhole", func(t *testing.T) {
+		body := `{"email": "valid@example.com", "notes": "` + strings.Repeat("x", MAX_MIDDLEWARE_BODY_SIZE) + `"}`
+		r := httptest.NewRequest("POST", "/signup?email=valid@example.com", strings.NewReader(body))
+		r.Header.Set("Content-Type", "application/json")
+		recorder := serve(MiddlewareOptions{}, r)
+
+		assert.Equal(t, 200, recorder.Code, "Expected the query string to supply the field")
+		assert.Equal(t, "valid|"+body, recorder.Body.String(), "Expected the whole body to reach the handler")
+	})
+
+	t.Run("TestFormRejected", func(t *testing.T) {
+		form := url.Values{"contact": {"invalid@example.com"}}
+		r := httptest.NewRequest("POST", "/signup", strings.NewReader(form.Encode()))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		recorder := serve(MiddlewareOptions{Field: "contact"}, r)
+
+		assert.Equal(t, 422, recorder.Code, "Expected invalid email to be rejected")
+		assert.Contains(t, recorder.Body.String(), "mailbox_not_found", "Expected rejection reason")
+	})
+
+	t.Run("TestSyntaxRejectedWithoutAPICall", func(t *testing.T) {
+		httpmock.ZeroCallCounters()
+		recorder := serve(MiddlewareOptions{}, httptest.NewRequest("GET", "/signup?email=nope", nil))
+
+		assert.Equal(t, 422, recorder.Code, "Expected malformed email to be rejected")
+		assert.Equal(t, 0, httpmock.GetTotalCallCount(), "Expected no API call for a malformed email")
+	})
+
+	t.Run("TestMissingField", func(t *testing.T) {
+		recorder := serve(MiddlewareOptions{}, httptest.NewRequest("GET", "/signup", nil))
+		assert.Equal(t, 200, recorder.Code, "Expected optional field to pass through")
+
+		recorder = serve(MiddlewareOptions{Required: true}, httptest.NewRequest("GET", "/signup", nil))
+		assert.Equal(t, 422, recorder.Code, "Expected required field to be enforced")
+	})
+
+	t.Run("TestAnnotateOnly", func(t *testing.T) {
+		recorder := serve(MiddlewareOptions{AnnotateOnly: true}, httptest.NewRequest("GET", "/signup?email=invalid@example.com", nil))
+		assert.Equal(t, 200, recorder.Code, "Expected annotate-only mode to pass through")
+		assert.Equal(t, "invalid|", recorder.Body.String(), "Expected the result in the request context")
+	})
+
+	t.Run("TestAPIFailure", func(t *testing.T) {
+		recorder := serve(MiddlewareOptions{}, httptest.NewRequest("GET", "/signup?email=down@example.com", nil))
+		assert.Equal(t, 503, recorder.Code, "Expected API failure to be rejected by default")
+
+		recorder = serve(MiddlewareOptions{FailOpen: true}, httptest.NewRequest("GET", "/signup?email=down@example.com", nil))
+		assert.Equal(t, 200, recorder.Code, "Expected API failure to pass when failing open")
+	})
+
+	t.Run("TestRequestContextCarried", func(t *testing.T) {
+		var rejected error
+		opts := MiddlewareOptions{OnReject: func(w http.ResponseWriter, r *http.Request, verification *RequestVerification) {
+			rejected = verification.Err
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}}
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		recorder := serve(opts, httptest.NewRequest("GET", "/signup?email=valid@example.com", nil).WithContext(ctx))
+
+		assert.Equal(t, http.StatusServiceUnavailable, recorder.Code, "Expected the abandoned request not to be validated")
+		assert.ErrorIs(t, rejected, context.Canceled, "Expected the client's cancellation to reach the API call")
+	})
+
+	t.Run("TestCache", func(t *testing.T) {
+		cache := NewMemoryValidationCache(0)
+		httpmock.ZeroCallCounters()
+
+		for i := 0; i < 3; i++ {
+			recorder := serve(MiddlewareOptions{Cache: cache}, httptest.NewRequest("GET", "/signup?email=valid@example.com", nil))
+			assert.Equal(t, 200, recorder.Code, "Expected valid email to pass")
+		}
+		assert.Equal(t, 1, httpmock.GetTotalCallCount(), "Expected repeated checks to be served from the cache")
+	})
+}
@@ -69,6 +69,18 @@ const (
 		}
 	}`
 	
+	MOCK_BATCH_IN_PROGRESS_RESPONSE = `{
+		"count_checked": 1,
+		"count_total": 3,
+		"name": "Test Batch",
+		"progress_percentage": 33,
+		"task_id": 12345,
+		"status": "in_progress",
+		"results": {
+			"email_batch": []
+		}
+	}`
+	
 	MOCK_FINDER_RESPONSE = `{
 		"email": "john.doe@example.com",
 		"status": "found"
@@ -84,6 +96,13 @@ const (
 		"daily_credits_limit": 150,
 		"remaining_credits": 15000
 	}`
+
+	MOCK_ACCOUNT_BALANCE_APPSUMO_RESPONSE = `{
+		"api_status": "enabled",
+		"daily_credits_limit": 150,
+		"remaining_daily_credits": 120,
+		"bonus_credits": 30
+	}`
 	
 	MOCK_ERROR_RESPONSE = `{
 		"error": "Invalid API key"
@@ -0,0 +1,232 @@
+package emailverifygo
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// PersonName is a name split into its parts, as accepted by FindEmailByName
+type PersonName struct {
+	Title  string // e.g. "Dr."
+	First  string
+	Middle string
+	Last   string // Includes particles and hyphenated parts, e.g. "van der Berg" or "García-López"
+	Suffix string // e.g. "Jr."
+}
+
+// Titles and suffixes recognised by ParseName, lower-cased without dots
+var (
+	nameTitles = map[string]bool{
+		"mr": true, "mrs": true, "ms": true, "miss": true, "mx": true, "dr": true, "prof": true,
+		"professor": true, "sir": true, "dame": true, "lord": true, "lady": true, "rev": true,
+		"fr": true, "sra": true, "srta": true, "don": true, "dona": true, "herr": true, "frau": true, "mme": true, "mlle": true,
+	}
+	// Titles that are also common first names or abbreviations, only taken as a
+	// title when a first and last name still follow
+	nameAmbiguousTitles = map[string]bool{"don": true, "dona": true, "fr": true}
+	nameSuffixes = map[string]bool{
+		"jr": true, "sr": true, "ii": true, "iii": true, "iv": true,
+		"phd": true, "md": true, "dds": true, "esq": true, "mba": true, "cpa": true,
+	}
+	// Particles that belong to the surname that follows them
+	nameParticles = map[string]bool{
+		"de": true, "del": true, "della": true, "der": true, "den": true, "di": true, "da": true,
+		"das": true, "dos": true, "du": true, "la": true, "le": true, "van": true, "von": true,
+		"ter": true, "ten": true, "bin": true, "binti": true, "al": true, "el": true,
+	}
+	// Conjunctions joining two surnames, e.g. "García y López"
+	nameConjunctions = map[string]bool{"y": true}
+)
+
+// ParseName splits a free-form name into title, first, middle, last name and
+// suffix. It understands "Last, First" order, titles such as "Dr.", suffixes
+// such as "Jr.", surname particles such as "van der" and surnames joined by
+// "y". Hyphenated parts are kept together.
+func ParseName(full string) PersonName {
+	var name PersonName
+
+	// "Garcia, Jose" is surname first, but "Jose Garcia, Jr." only sets off a suffix
+	parts := strings.Split(full, ",")
+	var suffixes []string
+	for len(parts) > 1 && isNameSuffix(strings.TrimSpace(parts[len(parts)-1])) {
+		suffixes = append([]string{strings.TrimSpace(parts[len(parts)-1])}, suffixes...)
+		parts = parts[:len(parts)-1]
+	}
+	if len(parts) > 1 {
+		full = strings.Join(parts[1:], " ") + " " + parts[0]
+	} else {
+		full = parts[0]
+	}
+
+	tokens := strings.Fields(full)
+	var titles []string
+	for len(tokens) > 1 && nameTitles[nameKey(tokens[0])] && (len(tokens) > 2 || !nameAmbiguousTitles[nameKey(tokens[0])]) {
+		titles = append(titles, tokens[0])
+		tokens = tokens[1:]
+	}
+	for len(tokens) > 1 && isNameSuffix(tokens[len(tokens)-1]) {
+		suffixes = append([]string{tokens[len(tokens)-1]}, suffixes...)
+		tokens = tokens[:len(tokens)-1]
+	}
+	name.Title = strings.Join(titles, " ")
+	name.Suffix = strings.Join(suffixes, " ")
+
+	switch len(tokens) {
+	case 0:
+		return name
+	case 1:
+		name.First = tokens[0]
+		return name
+	}
+
+	// The surname is the last token plus any particles before it, and the
+	// surname before it too when a conjunction joins them
+	lastStart := len(tokens) - 1
+	for lastStart > 1 {
+		key := nameKey(tokens[lastStart-1])
+		if nameParticles[key] {
+			lastStart--
+		} else if nameConjunctions[key] && lastStart > 2 {
+			lastStart -= 2
+		} else {
+			break
+		}
+	}
+	name.First = tokens[0]
+	name.Middle = strings.Join(tokens[1:lastStart], " ")
+	name.Last = strings.Join(tokens[lastStart:], " ")
+	return name
+}
+
+// String returns the first, middle and last names separated by spaces
+func (n PersonName) String() string {
+	return strings.Join(nonEmpty(n.First, n.Middle, n.Last), " ")
+}
+
+// Query returns the name as sent to the email finder: first and last name,
+// transliterated to ASCII
+func (n PersonName) Query() string {
+	return Transliterate(strings.Join(nonEmpty(n.First, n.Last), " "))
+}
+
+// FirstNameVariants returns the first name followed by the formal names it may
+// be a nickname for, e.g. "Bob", "Robert"
+func (n PersonName) FirstNameVariants() []string {
+	if n.First == "" {
+		return nil
+	}
+	variants := []string{n.First}
+	for _, formal := range nameNicknames[strings.ToLower(Transliterate(n.First))] {
+		variants = append(variants, strings.ToUpper(formal[:1])+formal[1:])
+	}
+	return variants
+}
+
+// FindEmailByName is like FindEmail but takes the name in parts. The first and
+// last name are transliterated to ASCII. When nothing is found and the first
+// name is a known nickname, the formal names are tried in turn; every attempt
+// costs a finder credit.
+//
+// Parameters:
+//   - name: The person's name, e.g. from ParseName
+//   - domain: The domain to search for the email on
+//
+// Returns:
+//   - *FindEmailResponse: The first result found, or the last not_found result
+//   - error: Any error that occurred during the requests
+//
+// API Reference: GET /api/v1/finder
+func FindEmailByName(name PersonName, domain string) (*FindEmailResponse, error) {
+	return FindEmailByNameContext(context.Background(), name, domain)
+}
+
+// FindEmailByNameContext is like FindEmailByName but carries ctx to the HTTP requests and tracing spans
+func FindEmailByNameContext(ctx context.Context, name PersonName, domain string) (*FindEmailResponse, error) {
+	if name.First == "" || name.Last == "" {
+		return nil, fmt.Errorf("Both first and last name are required")
+	}
+
+	var response *FindEmailResponse
+	for _, first := range name.FirstNameVariants() {
+		variant := name
+		variant.First = first
+
+		var err error
+		response, err = FindEmailContext(ctx, variant.Query(), domain)
+		if err != nil || response.IsFound() {
+			return response, err
+		}
+	}
+	return response, nil
+}
+
+// Transliterate replaces accented and other non-ASCII Latin letters with their
+// closest ASCII spelling, e.g. "José Núñez" becomes "Jose Nunez"
+func Transliterate(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if r <= unicode.MaxASCII {
+			b.WriteRune(r)
+			continue
+		}
+		replacement, ok := transliterations[unicode.ToLower(r)]
+		switch {
+		case !ok:
+			b.WriteRune(r)
+		case unicode.IsUpper(r):
+			b.WriteString(strings.ToUpper(replacement[:1]) + replacement[1:])
+		default:
+			b.WriteString(replacement)
+		}
+	}
+	return b.String()
+}
+
+// transliterations maps lower-case letters to their ASCII spelling
+var transliterations = map[rune]string{
+	'à': "a", 'á': "a", 'â': "a", 'ã': "a", 'ä': "a", 'å': "a", 'ā': "a", 'ă': "a", 'ą': "a",
+	'æ': "ae", 'ç': "c", 'ć': "c", 'č': "c", 'ď': "d", 'đ': "d", 'ð': "d",
+	'è': "e", 'é': "e", 'ê': "e", 'ë': "e", 'ē': "e", 'ė': "e", 'ę': "e", 'ě': "e",
+	'ğ': "g", 'ì': "i", 'í': "i", 'î': "i", 'ï': "i", 'ī': "i", 'į': "i", 'ı': "i",
+	'ł': "l", 'ľ': "l", 'ñ': "n", 'ń': "n", 'ň': "n",
+	'ò': "o", 'ó': "o", 'ô': "o", 'õ': "o", 'ö': "o", 'ø': "o", 'ō': "o", 'ő': "o", 'œ': "oe",
+	'ŕ': "r", 'ř': "r", 'ś': "s", 'š': "s", 'ş': "s", 'ș': "s", 'ß': "ss",
+	'ť': "t", 'ţ': "t", 'ț': "t", 'þ': "th",
+	'ù': "u", 'ú': "u", 'û': "u", 'ü': "u", 'ū': "u", 'ů': "u", 'ű': "u", 'ų': "u",
+	'ý': "y", 'ÿ': "y", 'ź': "z", 'ż': "z", 'ž': "z",
+}
+
+// nameNicknames maps common English nicknames to the formal names they stand for
+var nameNicknames = map[string][]string{
+	"abby": {"abigail"}, "alex": {"alexander", "alexandra"}, "andy": {"andrew"},
+	"ben": {"benjamin"}, "beth": {"elizabeth"}, "bill": {"william"}, "billy": {"william"},
+	"bob": {"robert"}, "bobby": {"robert"}, "chris": {"christopher", "christine"},
+	"dan": {"daniel"}, "danny": {"daniel"}, "dave": {"david"}, "ed": {"edward"},
+	"jen": {"jennifer"}, "jenny": {"jennifer"}, "jim": {"james"}, "jimmy": {"james"},
+	"joe": {"joseph"}, "johnny": {"john"}, "kate": {"katherine"}, "katie": {"katherine"},
+	"liz": {"elizabeth"}, "matt": {"matthew"}, "mike": {"michael"}, "nick": {"nicholas"},
+	"pat": {"patrick", "patricia"}, "peggy": {"margaret"}, "rob": {"robert"},
+	"sam": {"samuel", "samantha"}, "steve": {"steven", "stephen"}, "sue": {"susan"},
+	"ted": {"edward", "theodore"}, "tom": {"thomas"}, "tony": {"anthony"}, "will": {"william"},
+}
+
+// nameKey lower-cases a name token and drops dots, for table lookups
+func nameKey(token string) string {
+	return strings.ToLower(Transliterate(strings.ReplaceAll(token, ".", "")))
+}
+
+func isNameSuffix(token string) bool {
+	return nameSuffixes[nameKey(token)]
+}
+
+func nonEmpty(values ...string) []string {
+	var kept []string
+	for _, value := range values {
+		if value != "" {
+			kept = append(kept, value)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,114 @@
+package emailverifygo
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseName(t *testing.T) {
+	t.Run("TestTitlesAndSuffixes", func(t *testing.T) {
+		name := ParseName("Dr. José María García-López Jr.")
+		assert.Equal(t, PersonName{Title: "Dr.", First: "José", Middle: "María", Last: "García-López", Suffix: "Jr."}, name)
+		assert.Equal(t, "Jose Garcia-Lopez", name.Query(), "Expected the query to drop the middle name and diacritics")
+	})
+
+	t.Run("TestAmbiguousTitles", func(t *testing.T) {
+		assert.Equal(t, PersonName{First: "Don", Last: "Smith"}, ParseName("Don Smith"), "Expected Don to be a first name")
+		assert.Equal(t, PersonName{First: "Fr", Last: "Jones"}, ParseName("Fr Jones"))
+		assert.Equal(t, PersonName{Title: "Don", First: "Diego", Last: "Vega"}, ParseName("Don Diego Vega"))
+		assert.Equal(t, "Dr.", ParseName("Dr. Smith").Title, "Expected unambiguous titles to still be taken")
+	})
+
+	t.Run("TestParticles", func(t *testing.T) {
+		name := ParseName("Ludwig van der Rohe")
+		assert.Equal(t, "Ludwig", name.First)
+		assert.Equal(t, "", name.Middle)
+		assert.Equal(t, "van der Rohe", name.Last)
+
+		name = ParseName("Maria de la Cruz Santos")
+		assert.Equal(t, "de la Cruz", name.Middle, "Expected particles only to attach to the final surname")
+		assert.Equal(t, "Santos", name.Last)
+	})
+
+	t.Run("TestJoinedSurnames", func(t *testing.T) {
+		name := ParseName("José García y López")
+		assert.Equal(t, "José", name.First)
+		assert.Equal(t, "", name.Middle)
+		assert.Equal(t, "García y López", name.Last, "Expected both surnames joined by y")
+		assert.Equal(t, "Jose Garcia y Lopez", name.Query())
+
+		name = ParseName("José Luis Ortega y Gasset")
+		assert.Equal(t, "Luis", name.Middle)
+		assert.Equal(t, "Ortega y Gasset", name.Last)
+
+		name = ParseName("Ana de la Fuente y de León")
+		assert.Equal(t, "de la Fuente y de León", name.Last, "Expected particles on both sides of the conjunction")
+	})
+
+	t.Run("TestSurnameFirst", func(t *testing.T) {
+		name := ParseName("Núñez, Ana Sofía, PhD")
+		assert.Equal(t, PersonName{First: "Ana", Middle: "Sofía", Last: "Núñez", Suffix: "PhD"}, name)
+
+		name = ParseName("John Smith, Sr.")
+		assert.Equal(t, PersonName{First: "John", Last: "Smith", Suffix: "Sr."}, name)
+	})
+
+	t.Run("TestSingleName", func(t *testing.T) {
+		assert.Equal(t, PersonName{First: "Cher"}, ParseName("  Cher "))
+		assert.Equal(t, PersonName{}, ParseName(""))
+	})
+
+	t.Run("TestTransliterate", func(t *testing.T) {
+		assert.Equal(t, "Bjorn Strasse Lukasz Zizek", Transliterate("Bjørn Straße Łukasz Žižek"))
+		assert.Equal(t, "Aeneas", Transliterate("Æneas"))
+	})
+
+	t.Run("TestFirstNameVariants", func(t *testing.T) {
+		assert.Equal(t, []string{"Bob", "Robert"}, PersonName{First: "Bob"}.FirstNameVariants())
+		assert.Equal(t, []string{"Ana"}, PersonName{First: "Ana"}.FirstNameVariants())
+	})
+}
+
+func TestFindEmailByName(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	SetApiKey("test_api_key")
+
+	var names []string
+	httpmock.RegisterResponder("GET", `=~^(.*)`+ENDPOINT_EMAIL_FINDER+`(.*)\z`,
+		func(r *http.Request) (*http.Response, error) {
+			names = append(names, r.URL.Query().Get("name"))
+			if r.URL.Query().Get("name") == "Robert Nunez" {
+				return httpmock.NewStringResponse(200, `{"email": "robert.nunez@example.com", "status": "found"}`), nil
+			}
+			return httpmock.NewStringResponse(200, MOCK_FINDER_NOT_FOUND_RESPONSE), nil
+		},
+	)
+
+	t.Run("TestNicknameExpansion", func(t *testing.T) {
+		names = nil
+		response, err := FindEmailByName(ParseName("Mr. Bob Núñez"), "example.com")
+
+		assert.Nil(t, err, "Expected no error")
+		assert.Equal(t, "robert.nunez@example.com", response.Email)
+		assert.Equal(t, []string{"Bob Nunez", "Robert Nunez"}, names, "Expected the nickname to be tried first")
+	})
+
+	t.Run("TestNotFound", func(t *testing.T) {
+		names = nil
+		response, err := FindEmailByName(PersonName{First: "Ana", Last: "Núñez"}, "example.com")
+
+		assert.Nil(t, err, "Expected no error")
+		assert.False(t, response.IsFound())
+		assert.Equal(t, []string{"Ana Nunez"}, names, "Expected a single attempt without nicknames")
+	})
+
+	t.Run("TestMissingLastName", func(t *testing.T) {
+		_, err := FindEmailByName(PersonName{First: "Cher"}, "example.com")
+		assert.NotNil(t, err, "Expected an error without a last name")
+	})
+}
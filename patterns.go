@@ -0,0 +1,273 @@
+package emailverifygo
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// EmailPattern is a local-part format. {first} and {last} stand for the whole
+// first and last name, {f} and {l} for their initials.
+type EmailPattern string
+
+// Local-part formats recognised by PatternStore, in the order they are tried
+// when nothing has been learned about a domain
+const (
+	PATTERN_FIRST_DOT_LAST    EmailPattern = "{first}.{last}"
+	PATTERN_FIRST_LAST        EmailPattern = "{first}{last}"
+	PATTERN_F_LAST            EmailPattern = "{f}{last}"
+	PATTERN_FIRST             EmailPattern = "{first}"
+	PATTERN_F_DOT_LAST        EmailPattern = "{f}.{last}"
+	PATTERN_FIRST_UNDERSCORE  EmailPattern = "{first}_{last}"
+	PATTERN_FIRST_HYPHEN_LAST EmailPattern = "{first}-{last}"
+	PATTERN_FIRST_L           EmailPattern = "{first}{l}"
+	PATTERN_FIRST_DOT_L       EmailPattern = "{first}.{l}"
+	PATTERN_LAST_DOT_FIRST    EmailPattern = "{last}.{first}"
+	PATTERN_LAST_FIRST        EmailPattern = "{last}{first}"
+	PATTERN_LAST_F            EmailPattern = "{last}{f}"
+	PATTERN_LAST              EmailPattern = "{last}"
+	PATTERN_F_L               EmailPattern = "{f}{l}"
+)
+
+// EMAIL_PATTERNS lists every recognised pattern, most common first
+var EMAIL_PATTERNS = []EmailPattern{
+	PATTERN_FIRST_DOT_LAST, PATTERN_FIRST_LAST, PATTERN_F_LAST, PATTERN_FIRST, PATTERN_F_DOT_LAST,
+	PATTERN_FIRST_UNDERSCORE, PATTERN_FIRST_HYPHEN_LAST, PATTERN_FIRST_L, PATTERN_FIRST_DOT_L,
+	PATTERN_LAST_DOT_FIRST, PATTERN_LAST_FIRST, PATTERN_LAST_F, PATTERN_LAST, PATTERN_F_L,
+}
+
+// Where a result of FindEmailWithPatterns came from
+const (
+	EMAIL_SOURCE_PATTERN = "pattern" // A learned pattern, verified with Validate
+	EMAIL_SOURCE_FINDER  = "finder"  // The email finder API
+)
+
+// Apply builds the local part for a name, or returns "" when the name lacks a
+// part the pattern needs. Name parts are transliterated and lower-cased, and
+// anything but letters and digits is dropped, so "García-López" becomes "garcialopez".
+func (p EmailPattern) Apply(name PersonName) string {
+	first, last := localNamePart(name.First), localNamePart(name.Last)
+	pattern := string(p)
+	if (first == "" && strings.Contains(pattern, "{f")) || (last == "" && strings.Contains(pattern, "{l")) {
+		return ""
+	}
+
+	replacer := strings.NewReplacer("{first}", first, "{last}", last, "{f}", initial(first), "{l}", initial(last))
+	return replacer.Replace(pattern)
+}
+
+// PatternScore is how often a pattern matched the addresses learned for a domain
+type PatternScore struct {
+	Pattern EmailPattern
+	Count   int
+	Share   float64 // Count as a share of the addresses learned for the domain
+}
+
+// CandidateEmail is an address generated for a name from a pattern
+type CandidateEmail struct {
+	Email   string
+	Pattern EmailPattern
+	Share   float64 // The pattern's share of learned addresses, 0 if it was not learned
+}
+
+// PatternStore learns which local-part patterns each domain uses from
+// addresses known to be valid, and generates ranked candidates for new names
+type PatternStore struct {
+	mu      sync.Mutex
+	counts  map[string]map[EmailPattern]int
+	samples map[string]int
+}
+
+// NewPatternStore creates an empty store
+func NewPatternStore() *PatternStore {
+	return &PatternStore{counts: make(map[string]map[EmailPattern]int), samples: make(map[string]int)}
+}
+
+// Learn records the patterns a known valid address follows for the person's
+// name. It returns the matching patterns; an address can match several, such
+// as "john@" for {first} when the last name is unknown, or none.
+func (s *PatternStore) Learn(name PersonName, email string) []EmailPattern {
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return nil
+	}
+	local, domain := NormalizeEmail(email[:at]), NormalizeEmail(email[at+1:])
+
+	var matched []EmailPattern
+	for _, pattern := range EMAIL_PATTERNS {
+		if candidate := pattern.Apply(name); candidate != "" && candidate == local {
+			matched = append(matched, pattern)
+		}
+	}
+	if len(matched) == 0 {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.counts[domain] == nil {
+		s.counts[domain] = make(map[EmailPattern]int)
+	}
+	for _, pattern := range matched {
+		s.counts[domain][pattern]++
+	}
+	s.samples[domain]++
+	return matched
+}
+
+// LearnValidation learns from a validation result, if it says the address is valid
+func (s *PatternStore) LearnValidation(name PersonName, response *ValidateResponse) []EmailPattern {
+	if response == nil || !response.IsValid() {
+		return nil
+	}
+	return s.Learn(name, response.Email)
+}
+
+// Patterns returns the patterns learned for a domain, most frequent first
+func (s *PatternStore) Patterns(domain string) []PatternScore {
+	domain = NormalizeEmail(domain)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var scores []PatternScore
+	for pattern, count := range s.counts[domain] {
+		scores = append(scores, PatternScore{Pattern: pattern, Count: count, Share: float64(count) / float64(s.samples[domain])})
+	}
+	sort.SliceStable(scores, func(i, j int) bool {
+		if scores[i].Count != scores[j].Count {
+			return scores[i].Count > scores[j].Count
+		}
+		return patternRank(scores[i].Pattern) < patternRank(scores[j].Pattern)
+	})
+	return scores
+}
+
+// Candidates returns the addresses the learned patterns give for a name at the
+// domain, most likely first. It is empty when nothing was learned for the domain.
+func (s *PatternStore) Candidates(name PersonName, domain string) []CandidateEmail {
+	domain = NormalizeEmail(domain)
+
+	var candidates []CandidateEmail
+	seen := make(map[string]bool)
+	for _, score := range s.Patterns(domain) {
+		local := score.Pattern.Apply(name)
+		if local == "" || seen[local] {
+			continue
+		}
+		seen[local] = true
+		candidates = append(candidates, CandidateEmail{Email: local + "@" + domain, Pattern: score.Pattern, Share: score.Share})
+	}
+	return candidates
+}
+
+// PatternFindOptions configures FindEmailWithPatterns
+type PatternFindOptions struct {
+	// VerifyCandidates is how many learned candidates to validate before falling
+	// back to the email finder. Zero goes straight to the finder.
+	VerifyCandidates int
+}
+
+// PatternFindResult is the outcome of FindEmailWithPatterns
+type PatternFindResult struct {
+	Email       string             // The address found, empty if none
+	Pattern     EmailPattern       // The pattern the address follows, if known
+	Source      string             // EMAIL_SOURCE_PATTERN or EMAIL_SOURCE_FINDER
+	Validation  *ValidateResponse  // The validation that confirmed a pattern candidate
+	Finder      *FindEmailResponse // The finder result, when the finder was used
+	CreditsUsed int                // Validation and finder credits spent
+}
+
+// FindEmailWithPatterns looks for a person's address by validating the
+// candidates learned for the domain, most likely first, and falls back to
+// the email finder when none is valid. Validation stops early on a catch-all
+// domain, where it cannot tell candidates apart. Addresses found either way
+// are learned. The finder is called once, with the name as given.
+//
+// Parameters:
+//   - ctx: Carried to every request
+//   - store: The learned patterns
+//   - name: The person's name, e.g. from ParseName
+//   - domain: The domain to search on, see ExtractDomain
+//   - opts: How many candidates to verify
+//
+// Returns:
+//   - *PatternFindResult: The address found and how
+//   - error: Any error from the finder; failed validations only skip their candidate
+func FindEmailWithPatterns(ctx context.Context, store *PatternStore, name PersonName, domain string, opts PatternFindOptions) (*PatternFindResult, error) {
+	domain, err := ExtractDomain(domain)
+	if err != nil {
+		return nil, err
+	}
+	result := &PatternFindResult{}
+
+	candidates := store.Candidates(name, domain)
+	for i, candidate := range candidates {
+		if i >= opts.VerifyCandidates {
+			break
+		}
+		validation, credits, err := validateContext(ctx, candidate.Email)
+		if err != nil {
+			if ctx.Err() != nil {
+				return result, err
+			}
+			continue
+		}
+		result.CreditsUsed += credits
+		if validation.IsValid() {
+			result.Email, result.Pattern, result.Source, result.Validation = candidate.Email, candidate.Pattern, EMAIL_SOURCE_PATTERN, validation
+			store.Learn(name, candidate.Email)
+			return result, nil
+		}
+		if validation.Status == STATUS_CATCH_ALL {
+			break
+		}
+	}
+
+	if name.First == "" || name.Last == "" {
+		return result, fmt.Errorf("Both first and last name are required")
+	}
+	finder, err := FindEmailContext(ctx, name.Query(), domain)
+	if err != nil {
+		return result, fmt.Errorf("failed to find email: %w", err)
+	}
+	result.Source, result.Finder = EMAIL_SOURCE_FINDER, finder
+	result.CreditsUsed += CREDITS_PER_FINDER
+	if finder.IsFound() {
+		result.Email = finder.Email
+		if matched := store.Learn(name, finder.Email); len(matched) > 0 {
+			result.Pattern = matched[0]
+		}
+	}
+	return result, nil
+}
+
+// localNamePart reduces a name part to the letters and digits used in local parts
+func localNamePart(part string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(Transliterate(part)) {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+func initial(part string) string {
+	if part == "" {
+		return ""
+	}
+	return part[:1]
+}
+
+// patternRank orders patterns by how common they are, for ties between learned counts
+func patternRank(pattern EmailPattern) int {
+	for i, known := range EMAIL_PATTERNS {
+		if known == pattern {
+			return i
+		}
+	}
+	return len(EMAIL_PATTERNS)
+}
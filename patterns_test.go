@@ -0,0 +1,125 @@
+package emailverifygo
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPatternStore(t *testing.T) {
+	t.Run("TestApply", func(t *testing.T) {
+		name := ParseName("José García-López")
+		assert.Equal(t, "jose.garcialopez", PATTERN_FIRST_DOT_LAST.Apply(name))
+		assert.Equal(t, "jgarcialopez", PATTERN_F_LAST.Apply(name))
+		assert.Equal(t, "joseg", PATTERN_FIRST_L.Apply(name))
+		assert.Equal(t, "", PATTERN_F_LAST.Apply(PersonName{First: "Cher"}), "Expected no local part without a last name")
+	})
+
+	t.Run("TestLearnAndRank", func(t *testing.T) {
+		store := NewPatternStore()
+		store.Learn(ParseName("John Doe"), "jdoe@acme.com")
+		store.Learn(ParseName("Jane Roe"), "JRoe@Acme.com")
+		store.Learn(ParseName("Max Mustermann"), "max.mustermann@acme.com")
+		assert.Nil(t, store.Learn(ParseName("Info Desk"), "sales@acme.com"), "Expected an unrelated address to teach nothing")
+
+		patterns := store.Patterns("acme.com")
+		if assert.Len(t, patterns, 2) {
+			assert.Equal(t, PATTERN_F_LAST, patterns[0].Pattern, "Expected the dominant pattern first")
+			assert.Equal(t, 2, patterns[0].Count)
+			assert.InDelta(t, 2.0/3, patterns[0].Share, 0.001)
+			assert.Equal(t, PATTERN_FIRST_DOT_LAST, patterns[1].Pattern)
+		}
+
+		candidates := store.Candidates(ParseName("Ann Smith"), "ACME.com")
+		if assert.Len(t, candidates, 2) {
+			assert.Equal(t, "asmith@acme.com", candidates[0].Email)
+			assert.Equal(t, "ann.smith@acme.com", candidates[1].Email)
+		}
+		assert.Empty(t, store.Candidates(ParseName("Ann Smith"), "other.com"), "Expected no candidates for an unknown domain")
+	})
+
+	t.Run("TestLearnValidation", func(t *testing.T) {
+		store := NewPatternStore()
+		store.LearnValidation(ParseName("John Doe"), &ValidateResponse{Email: "john@acme.com", Status: STATUS_INVALID})
+		assert.Empty(t, store.Patterns("acme.com"), "Expected invalid addresses not to be learned")
+
+		store.LearnValidation(ParseName("John Doe"), &ValidateResponse{Email: "john@acme.com", Status: STATUS_VALID})
+		assert.Equal(t, PATTERN_FIRST, store.Patterns("acme.com")[0].Pattern)
+	})
+}
+
+func TestFindEmailWithPatterns(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	SetApiKey("test_api_key")
+
+	statuses := map[string]string{
+		"asmith@acme.com":       STATUS_INVALID,
+		"ann.smith@acme.com":    STATUS_VALID,
+		"bob.jones@catchall.io": STATUS_CATCH_ALL,
+	}
+	httpmock.RegisterResponder("GET", `=~^(.*)`+ENDPOINT_VALIDATE+`(.*)\z`,
+		func(r *http.Request) (*http.Response, error) {
+			email := r.URL.Query().Get("email")
+			status, ok := statuses[email]
+			if !ok {
+				status = STATUS_INVALID
+			}
+			return httpmock.NewStringResponse(200, fmt.Sprintf(`{"email": %q, "status": %q, "sub_status": ""}`, email, status)), nil
+		},
+	)
+	httpmock.RegisterResponder("GET", `=~^(.*)`+ENDPOINT_EMAIL_FINDER+`(.*)\z`,
+		func(r *http.Request) (*http.Response, error) {
+			if r.URL.Query().Get("name") == "Bob Jones" {
+				return httpmock.NewStringResponse(200, `{"email": "bjones@catchall.io", "status": "found"}`), nil
+			}
+			return httpmock.NewStringResponse(200, MOCK_FINDER_NOT_FOUND_RESPONSE), nil
+		},
+	)
+
+	ctx := context.Background()
+	store := NewPatternStore()
+	store.Learn(ParseName("John Doe"), "jdoe@acme.com")
+	store.Learn(ParseName("John Doe"), "jdoe@acme.com")
+	store.Learn(ParseName("Max Mustermann"), "max.mustermann@acme.com")
+	store.Learn(ParseName("Jim Beam"), "jim.beam@catchall.io")
+
+	t.Run("TestVerifiedCandidate", func(t *testing.T) {
+		httpmock.ZeroCallCounters()
+		result, err := FindEmailWithPatterns(ctx, store, ParseName("Ann Smith"), "https://www.acme.com", PatternFindOptions{VerifyCandidates: 3})
+
+		assert.Nil(t, err, "Expected no error")
+		assert.Equal(t, "ann.smith@acme.com", result.Email, "Expected the second candidate after the first failed")
+		assert.Equal(t, EMAIL_SOURCE_PATTERN, result.Source)
+		assert.Equal(t, PATTERN_FIRST_DOT_LAST, result.Pattern)
+		assert.Equal(t, 2, result.CreditsUsed)
+		assert.Equal(t, 2, httpmock.GetTotalCallCount(), "Expected no finder call")
+	})
+
+	t.Run("TestCatchAllFallsBackToFinder", func(t *testing.T) {
+		httpmock.ZeroCallCounters()
+		result, err := FindEmailWithPatterns(ctx, store, ParseName("Bob Jones"), "catchall.io", PatternFindOptions{VerifyCandidates: 3})
+
+		assert.Nil(t, err, "Expected no error")
+		assert.Equal(t, EMAIL_SOURCE_FINDER, result.Source)
+		assert.Equal(t, "bjones@catchall.io", result.Email)
+		assert.Equal(t, PATTERN_F_LAST, result.Pattern, "Expected the finder result's pattern to be recognised")
+		assert.Equal(t, 2, result.CreditsUsed, "Expected verification to stop at the catch-all result")
+		assert.Equal(t, 2, len(store.Patterns("catchall.io")), "Expected the finder result to be learned")
+	})
+
+	t.Run("TestNoVerification", func(t *testing.T) {
+		httpmock.ZeroCallCounters()
+		result, err := FindEmailWithPatterns(ctx, store, ParseName("Ann Smith"), "acme.com", PatternFindOptions{})
+
+		assert.Nil(t, err, "Expected no error")
+		assert.Equal(t, EMAIL_SOURCE_FINDER, result.Source)
+		assert.Empty(t, result.Email)
+		assert.Equal(t, 1, httpmock.GetTotalCallCount(), "Expected only the finder to be called")
+	})
+}
@@ -0,0 +1,139 @@
+package emailverifygo
+
+import (
+	"context"
+	"fmt"
+)
+
+// DEFAULT_PERMUTATION_BUDGET is the most credits VerifyPermutations spends per lookup by default
+const DEFAULT_PERMUTATION_BUDGET = 5
+
+// GenerateCandidates returns candidate addresses for a name at a domain, one
+// per recognised pattern. Patterns learned by store, if given, come first in
+// their learned order, followed by the rest of EMAIL_PATTERNS.
+func GenerateCandidates(name PersonName, domain string, store *PatternStore) []CandidateEmail {
+	domain = NormalizeEmail(domain)
+
+	var candidates []CandidateEmail
+	seen := make(map[string]bool)
+	if store != nil {
+		for _, candidate := range store.Candidates(name, domain) {
+			seen[candidate.Email] = true
+			candidates = append(candidates, candidate)
+		}
+	}
+	for _, pattern := range EMAIL_PATTERNS {
+		local := pattern.Apply(name)
+		if local == "" || seen[local+"@"+domain] {
+			continue
+		}
+		seen[local+"@"+domain] = true
+		candidates = append(candidates, CandidateEmail{Email: local + "@" + domain, Pattern: pattern})
+	}
+	return candidates
+}
+
+// PermutationOptions configures VerifyPermutations
+type PermutationOptions struct {
+	// Budget is the most credits one lookup may spend, DEFAULT_PERMUTATION_BUDGET if zero
+	Budget int
+	// Store ranks learned patterns first and learns the address found
+	Store *PatternStore
+	// FinderFirst calls the email finder before trying permutations. Its credit counts against the budget.
+	FinderFirst bool
+}
+
+// CandidateAttempt is one candidate validated by VerifyPermutations
+type CandidateAttempt struct {
+	Candidate  CandidateEmail
+	Validation *ValidateResponse // Nil when validation failed
+	Err        error
+}
+
+// PermutationResult is the outcome of VerifyPermutations
+type PermutationResult struct {
+	Email           string             // The address found, empty if none
+	Pattern         EmailPattern       // The pattern of a permutation found, empty for a finder result
+	Source          string             // EMAIL_SOURCE_PATTERN or EMAIL_SOURCE_FINDER, empty if nothing was found
+	Finder          *FindEmailResponse // The finder result, when FinderFirst was set
+	Attempts        []CandidateAttempt // Every candidate validated, in order
+	CreditsUsed     int
+	CatchAll        bool // Validation stopped because the domain accepts every address
+	BudgetExhausted bool // Validation stopped because the budget ran out
+}
+
+// VerifyPermutations looks for a person's address by validating candidate
+// permutations in order, stopping at the first valid address, when the budget
+// is spent, or at a catch-all result, since a catch-all domain would accept
+// every remaining candidate too.
+//
+// Parameters:
+//   - ctx: Carried to every request
+//   - name: The person's name, e.g. from ParseName
+//   - domain: The domain to search on, see ExtractDomain
+//   - opts: The credit budget, pattern store and whether to ask the finder first
+//
+// Returns:
+//   - *PermutationResult: The address found and every attempt
+//   - error: Any error from the finder, an invalid domain or ctx being done;
+//     failed validations are recorded as attempts
+func VerifyPermutations(ctx context.Context, name PersonName, domain string, opts PermutationOptions) (*PermutationResult, error) {
+	if opts.Budget <= 0 {
+		opts.Budget = DEFAULT_PERMUTATION_BUDGET
+	}
+	domain, err := ExtractDomain(domain)
+	if err != nil {
+		return nil, err
+	}
+	result := &PermutationResult{}
+
+	if opts.FinderFirst {
+		if name.First == "" || name.Last == "" {
+			return nil, fmt.Errorf("Both first and last name are required")
+		}
+		finder, err := FindEmailContext(ctx, name.Query(), domain)
+		if err != nil {
+			return result, fmt.Errorf("failed to find email: %w", err)
+		}
+		result.Finder = finder
+		result.CreditsUsed += CREDITS_PER_FINDER
+		if finder.IsFound() {
+			result.Email, result.Source = finder.Email, EMAIL_SOURCE_FINDER
+			if opts.Store != nil {
+				opts.Store.Learn(name, finder.Email)
+			}
+			return result, nil
+		}
+	}
+
+	for _, candidate := range GenerateCandidates(name, domain, opts.Store) {
+		if result.CreditsUsed+CREDITS_PER_VALIDATION > opts.Budget {
+			result.BudgetExhausted = true
+			break
+		}
+
+		validation, credits, err := validateContext(ctx, candidate.Email)
+		if err != nil {
+			result.Attempts = append(result.Attempts, CandidateAttempt{Candidate: candidate, Err: err})
+			if ctx.Err() != nil {
+				return result, err
+			}
+			continue
+		}
+		result.Attempts = append(result.Attempts, CandidateAttempt{Candidate: candidate, Validation: validation})
+		result.CreditsUsed += credits
+
+		if validation.IsValid() {
+			result.Email, result.Pattern, result.Source = candidate.Email, candidate.Pattern, EMAIL_SOURCE_PATTERN
+			if opts.Store != nil {
+				opts.Store.Learn(name, candidate.Email)
+			}
+			return result, nil
+		}
+		if validation.Status == STATUS_CATCH_ALL {
+			result.CatchAll = true
+			break
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,117 @@
+package emailverifygo
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateCandidates(t *testing.T) {
+	name := ParseName("Ann Smith")
+
+	candidates := GenerateCandidates(name, "Acme.com", nil)
+	assert.Len(t, candidates, len(EMAIL_PATTERNS), "Expected one candidate per pattern")
+	assert.Equal(t, "ann.smith@acme.com", candidates[0].Email)
+	assert.Equal(t, "annsmith@acme.com", candidates[1].Email)
+	assert.Equal(t, "asmith@acme.com", candidates[2].Email)
+
+	store := NewPatternStore()
+	store.Learn(ParseName("John Doe"), "doe.john@acme.com")
+	candidates = GenerateCandidates(name, "acme.com", store)
+	assert.Len(t, candidates, len(EMAIL_PATTERNS), "Expected learned patterns not to be repeated")
+	assert.Equal(t, "smith.ann@acme.com", candidates[0].Email, "Expected learned patterns first")
+	assert.Equal(t, "ann.smith@acme.com", candidates[1].Email)
+}
+
+func TestVerifyPermutations(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	SetApiKey("test_api_key")
+
+	statuses := map[string]string{
+		"asmith@acme.com":       STATUS_VALID,
+		"bob.jones@catchall.io": STATUS_CATCH_ALL,
+		"ann.smith@failing.com": "",
+	}
+	httpmock.RegisterResponder("GET", `=~^(.*)`+ENDPOINT_VALIDATE+`(.*)\z`,
+		func(r *http.Request) (*http.Response, error) {
+			email := r.URL.Query().Get("email")
+			status, ok := statuses[email]
+			if ok && status == "" {
+				return httpmock.NewStringResponse(500, MOCK_ERROR_RESPONSE), nil
+			}
+			if !ok {
+				status = STATUS_INVALID
+			}
+			return httpmock.NewStringResponse(200, fmt.Sprintf(`{"email": %q, "status": %q, "sub_status": ""}`, email, status)), nil
+		},
+	)
+	httpmock.RegisterResponder("GET", `=~^(.*)`+ENDPOINT_EMAIL_FINDER+`(.*)\z`,
+		httpmock.NewStringResponder(200, MOCK_FINDER_NOT_FOUND_RESPONSE))
+
+	ctx := context.Background()
+
+	t.Run("TestFirstValid", func(t *testing.T) {
+		store := NewPatternStore()
+		result, err := VerifyPermutations(ctx, ParseName("Ann Smith"), "acme.com", PermutationOptions{Store: store, FinderFirst: true})
+
+		assert.Nil(t, err, "Expected no error")
+		assert.Equal(t, "asmith@acme.com", result.Email)
+		assert.Equal(t, PATTERN_F_LAST, result.Pattern)
+		assert.Equal(t, EMAIL_SOURCE_PATTERN, result.Source)
+		assert.Len(t, result.Attempts, 3, "Expected validation to stop at the first valid candidate")
+		assert.Equal(t, 4, result.CreditsUsed, "Expected the finder credit to count")
+		assert.Equal(t, PATTERN_F_LAST, store.Patterns("acme.com")[0].Pattern, "Expected the hit to be learned")
+	})
+
+	t.Run("TestBudget", func(t *testing.T) {
+		result, err := VerifyPermutations(ctx, ParseName("Nobody Here"), "acme.com", PermutationOptions{Budget: 3})
+
+		assert.Nil(t, err, "Expected no error")
+		assert.Empty(t, result.Email)
+		assert.True(t, result.BudgetExhausted, "Expected the budget to stop validation")
+		assert.Equal(t, 3, result.CreditsUsed)
+		assert.Len(t, result.Attempts, 3)
+	})
+
+	t.Run("TestFreeAnswersDoNotSpendBudget", func(t *testing.T) {
+		defer SetDomainIntelligence(nil)
+		intelligence := NewDomainIntelligence(DomainIntelligenceOptions{SkipDead: true})
+		intelligence.Observe("anyone@dead.com", STATUS_INVALID, SUBSTATUS_NO_DNS_ENTRIES)
+		SetDomainIntelligence(intelligence)
+		httpmock.ZeroCallCounters()
+
+		result, err := VerifyPermutations(ctx, ParseName("Ann Smith"), "dead.com", PermutationOptions{Budget: 1})
+
+		assert.Nil(t, err, "Expected no error")
+		assert.False(t, result.BudgetExhausted, "Expected free answers not to exhaust the budget")
+		assert.Equal(t, 0, result.CreditsUsed)
+		assert.Len(t, result.Attempts, len(GenerateCandidates(ParseName("Ann Smith"), "dead.com", nil)))
+		assert.Equal(t, 0, httpmock.GetTotalCallCount(), "Expected no API call")
+	})
+
+	t.Run("TestCatchAll", func(t *testing.T) {
+		result, err := VerifyPermutations(ctx, ParseName("Bob Jones"), "catchall.io", PermutationOptions{})
+
+		assert.Nil(t, err, "Expected no error")
+		assert.Empty(t, result.Email, "Expected a catch-all result not to count as a hit")
+		assert.True(t, result.CatchAll)
+		assert.Equal(t, 1, result.CreditsUsed)
+	})
+
+	t.Run("TestFailedValidation", func(t *testing.T) {
+		result, err := VerifyPermutations(ctx, ParseName("Ann Smith"), "failing.com", PermutationOptions{Budget: 2})
+
+		assert.Nil(t, err, "Expected failed validations not to end the lookup")
+		if assert.Len(t, result.Attempts, 3) {
+			assert.NotNil(t, result.Attempts[0].Err)
+			assert.Nil(t, result.Attempts[0].Validation)
+		}
+		assert.Equal(t, 2, result.CreditsUsed, "Expected failed validations not to spend the budget")
+	})
+}
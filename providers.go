@@ -0,0 +1,244 @@
+package emailverifygo
+
+import (
+	"context"
+	"sort"
+	"strings"
+)
+
+// Mailbox providers and security gateways recognised from MX hosts
+const (
+	PROVIDER_GOOGLE      = "google_workspace"
+	PROVIDER_MICROSOFT   = "microsoft_365"
+	PROVIDER_ZOHO        = "zoho"
+	PROVIDER_YAHOO       = "yahoo"
+	PROVIDER_ICLOUD      = "icloud"
+	PROVIDER_FASTMAIL    = "fastmail"
+	PROVIDER_PROOFPOINT  = "proofpoint"
+	PROVIDER_MIMECAST    = "mimecast"
+	PROVIDER_BARRACUDA   = "barracuda"
+	PROVIDER_CISCO       = "cisco_secure_email"
+	PROVIDER_SELF_HOSTED = "self_hosted" // The MX hosts are on the domain itself
+	PROVIDER_OTHER       = "other"       // The MX hosts belong to a provider not listed
+	PROVIDER_NONE        = "none"        // The domain cannot receive mail
+)
+
+// Kinds of provider
+const (
+	PROVIDER_KIND_MAILBOX = "mailbox" // Hosts the mailboxes, so it answers for them directly
+	PROVIDER_KIND_GATEWAY = "gateway" // Filters mail before passing it on, hiding the mailbox provider
+)
+
+// MailProvider maps MX host suffixes to a provider
+type MailProvider struct {
+	Name     string
+	Kind     string
+	Suffixes []string // MX hosts equal to or under one of these belong to the provider
+}
+
+// MAIL_PROVIDERS lists the providers ClassifyMX recognises
+var MAIL_PROVIDERS = []MailProvider{
+	{Name: PROVIDER_GOOGLE, Kind: PROVIDER_KIND_MAILBOX, Suffixes: []string{"google.com", "googlemail.com"}},
+	{Name: PROVIDER_MICROSOFT, Kind: PROVIDER_KIND_MAILBOX, Suffixes: []string{"protection.outlook.com", "outlook.com"}},
+	{Name: PROVIDER_ZOHO, Kind: PROVIDER_KIND_MAILBOX, Suffixes: []string{"zoho.com", "zoho.eu", "zoho.in", "zohomail.com"}},
+	{Name: PROVIDER_YAHOO, Kind: PROVIDER_KIND_MAILBOX, Suffixes: []string{"yahoodns.net"}},
+	{Name: PROVIDER_ICLOUD, Kind: PROVIDER_KIND_MAILBOX, Suffixes: []string{"mail.icloud.com"}},
+	{Name: PROVIDER_FASTMAIL, Kind: PROVIDER_KIND_MAILBOX, Suffixes: []string{"messagingengine.com"}},
+	{Name: PROVIDER_PROOFPOINT, Kind: PROVIDER_KIND_GATEWAY, Suffixes: []string{"pphosted.com", "ppe-hosted.com"}},
+	{Name: PROVIDER_MIMECAST, Kind: PROVIDER_KIND_GATEWAY, Suffixes: []string{"mimecast.com", "mimecast.co.za"}},
+	{Name: PROVIDER_BARRACUDA, Kind: PROVIDER_KIND_GATEWAY, Suffixes: []string{"barracudanetworks.com"}},
+	{Name: PROVIDER_CISCO, Kind: PROVIDER_KIND_GATEWAY, Suffixes: []string{"iphmx.com"}},
+}
+
+// ProviderClassification is the provider a domain's mail goes to
+type ProviderClassification struct {
+	Domain   string   `json:"domain"`
+	Provider string   `json:"provider"` // One of the PROVIDER_* values
+	Kind     string   `json:"kind"`     // PROVIDER_KIND_MAILBOX or PROVIDER_KIND_GATEWAY, empty for other and none
+	MX       []string `json:"mx"`       // The MX hosts classified, most preferred first
+}
+
+// IsGateway returns true when a security gateway receives the domain's mail
+func (c *ProviderClassification) IsGateway() bool {
+	return c.Kind == PROVIDER_KIND_GATEWAY
+}
+
+// ClassifyMX classifies a domain by its MX hosts, most preferred first. The
+// most preferred host that belongs to a known provider decides; failing that,
+// hosts on the domain itself make it self-hosted.
+func ClassifyMX(domain string, mx []string) *ProviderClassification {
+	domain = strings.TrimSuffix(NormalizeEmail(domain), ".")
+	classification := &ProviderClassification{Domain: domain, Provider: PROVIDER_NONE, MX: mx}
+	if len(mx) == 0 {
+		return classification
+	}
+
+	for _, host := range mx {
+		host = strings.TrimSuffix(NormalizeEmail(host), ".")
+		if provider, ok := providerForHost(host); ok {
+			classification.Provider, classification.Kind = provider.Name, provider.Kind
+			return classification
+		}
+	}
+
+	classification.Provider = PROVIDER_OTHER
+	if sameRegistrableDomain(strings.TrimSuffix(NormalizeEmail(mx[0]), "."), domain) {
+		classification.Provider, classification.Kind = PROVIDER_SELF_HOSTED, PROVIDER_KIND_MAILBOX
+	}
+	return classification
+}
+
+// ClassifyProvider looks up a domain's MX records and classifies them. A
+// domain receiving mail at its own address, without MX records, is self-hosted.
+func (c *DomainChecker) ClassifyProvider(ctx context.Context, domain string) (*ProviderClassification, error) {
+	health, err := c.Check(ctx, domain)
+	if err != nil {
+		return nil, err
+	}
+	if !health.AcceptsMail() {
+		return ClassifyMX(health.Domain, nil), nil
+	}
+	if len(health.MX) == 0 {
+		return &ProviderClassification{Domain: health.Domain, Provider: PROVIDER_SELF_HOSTED, Kind: PROVIDER_KIND_MAILBOX}, nil
+	}
+	return ClassifyMX(health.Domain, health.MX), nil
+}
+
+// providerDetection classifies the domains of validated addresses. Nil disables it.
+var providerDetection *DomainChecker
+
+// SetProviderDetection makes Validate attach the provider of each address's
+// domain to its result, looked up with checker. Lookup failures leave the
+// provider unset. Pass nil to disable it, which is the default.
+func SetProviderDetection(checker *DomainChecker) {
+	providerDetection = checker
+}
+
+// attachProvider classifies the domain of a validation result, if detection is on
+func attachProvider(ctx context.Context, response *ValidateResponse) {
+	if providerDetection == nil || response == nil {
+		return
+	}
+	if provider, err := providerDetection.ClassifyProvider(ctx, emailDomain(response.Email)); err == nil {
+		response.Provider = provider
+	}
+}
+
+// attachBatchProviders classifies the domains of a batch's results, if
+// detection is on, looking each domain up once
+func attachBatchProviders(ctx context.Context, results []EmailBatchResult) {
+	if providerDetection == nil {
+		return
+	}
+	classified := make(map[string]*ProviderClassification)
+	for i := range results {
+		domain := emailDomain(results[i].Address)
+		if domain == "" {
+			continue
+		}
+		provider, seen := classified[domain]
+		if !seen {
+			provider, _ = providerDetection.ClassifyProvider(ctx, domain)
+			classified[domain] = provider
+		}
+		results[i].Provider = provider
+	}
+}
+
+// ProviderSummary counts the results of a batch going to one provider
+type ProviderSummary struct {
+	Provider  string   `json:"provider"`
+	Kind      string   `json:"kind"`
+	Domains   []string `json:"domains"`   // The domains classified as this provider
+	Addresses int      `json:"addresses"` // The results on those domains
+}
+
+// ClassifyProviders classifies the domains of a batch's results, sets the
+// Provider of each result, and adds a summary per provider to the report,
+// largest first. Results already carrying a provider keep it. Domains whose
+// lookup failed are counted under PROVIDER_OTHER and their results left unset.
+//
+// Parameters:
+//   - ctx: Carried to the DNS lookups
+//   - checker: Looks up the MX records; the SetProviderDetection checker if nil
+//   - results: The response from GetBatchResults
+func (r *BatchReconciliation) ClassifyProviders(ctx context.Context, checker *DomainChecker, results *BatchResultResponse) {
+	if checker == nil {
+		checker = providerDetection
+	}
+	if checker == nil || results == nil {
+		return
+	}
+
+	summaries := make(map[string]*ProviderSummary)
+	classified := make(map[string]*ProviderClassification)
+	for i := range results.Results.EmailBatch {
+		result := &results.Results.EmailBatch[i]
+		domain := emailDomain(result.Address)
+		if domain == "" {
+			continue
+		}
+
+		classification, seen := classified[domain]
+		if !seen {
+			classification = result.Provider
+			if classification == nil {
+				classification, _ = checker.ClassifyProvider(ctx, domain)
+			}
+			classified[domain] = classification
+
+			provider, kind := PROVIDER_OTHER, ""
+			if classification != nil {
+				provider, kind = classification.Provider, classification.Kind
+			}
+			if summaries[provider] == nil {
+				summaries[provider] = &ProviderSummary{Provider: provider, Kind: kind}
+			}
+			summaries[provider].Domains = append(summaries[provider].Domains, domain)
+		}
+		if result.Provider == nil {
+			result.Provider = classification
+		}
+
+		provider := PROVIDER_OTHER
+		if classification != nil {
+			provider = classification.Provider
+		}
+		summaries[provider].Addresses++
+	}
+
+	r.Providers = r.Providers[:0]
+	for _, summary := range summaries {
+		r.Providers = append(r.Providers, *summary)
+	}
+	sort.Slice(r.Providers, func(i, j int) bool {
+		if r.Providers[i].Addresses != r.Providers[j].Addresses {
+			return r.Providers[i].Addresses > r.Providers[j].Addresses
+		}
+		return r.Providers[i].Provider < r.Providers[j].Provider
+	})
+}
+
+func providerForHost(host string) (MailProvider, bool) {
+	for _, provider := range MAIL_PROVIDERS {
+		for _, suffix := range provider.Suffixes {
+			if host == suffix || strings.HasSuffix(host, "."+suffix) {
+				return provider, true
+			}
+		}
+	}
+	return MailProvider{}, false
+}
+
+// sameRegistrableDomain returns true if both hosts share a registrable domain
+func sameRegistrableDomain(a, b string) bool {
+	partsA, err := ParseDomain(a)
+	if err != nil {
+		return false
+	}
+	partsB, err := ParseDomain(b)
+	if err != nil {
+		return false
+	}
+	return partsA.Domain == partsB.Domain
+}
@@ -0,0 +1,240 @@
+package emailverifygo
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func providerResolver() *StaticResolver {
+	return &StaticResolver{
+		MX: map[string][]*net.MX{
+			"gmail-corp.com": {{Host: "aspmx.l.google.com.", Pref: 1}, {Host: "alt1.aspmx.l.google.com.", Pref: 5}},
+			"ms-corp.com":    {{Host: "ms-corp-com.mail.protection.outlook.com.", Pref: 0}},
+			"secure.com":     {{Host: "mx0a-001.pphosted.com.", Pref: 10}},
+			"acme.co.uk":     {{Host: "mail.acme.co.uk.", Pref: 10}},
+			"nullmx.com":     {{Host: ".", Pref: 0}},
+			"example.com":    {{Host: "aspmx.l.google.com.", Pref: 1}},
+		},
+		Hosts: map[string][]string{
+			"tiny.org": {"192.0.2.1"},
+		},
+	}
+}
+
+func TestClassifyMX(t *testing.T) {
+	t.Run("TestKnownProviders", func(t *testing.T) {
+		cases := map[string]string{
+			"aspmx.l.google.com":                   PROVIDER_GOOGLE,
+			"acme-com.mail.protection.outlook.com": PROVIDER_MICROSOFT,
+			"mx.zoho.eu":                           PROVIDER_ZOHO,
+			"mx0b-0012.pphosted.com.":              PROVIDER_PROOFPOINT,
+			"eu-smtp-inbound-1.mimecast.com":       PROVIDER_MIMECAST,
+			"d123.ess.barracudanetworks.com":       PROVIDER_BARRACUDA,
+			"mx1.hc123-45.iphmx.com":               PROVIDER_CISCO,
+			"in1-smtp.messagingengine.com":         PROVIDER_FASTMAIL,
+		}
+		for host, provider := range cases {
+			assert.Equal(t, provider, ClassifyMX("acme.com", []string{host}).Provider, "Unexpected provider for %s", host)
+		}
+	})
+
+	t.Run("TestGateway", func(t *testing.T) {
+		classification := ClassifyMX("acme.com", []string{"mx1.acme-gw.mimecast.com"})
+		assert.True(t, classification.IsGateway())
+		assert.False(t, ClassifyMX("acme.com", []string{"aspmx.l.google.com"}).IsGateway())
+	})
+
+	t.Run("TestPreferredHostDecides", func(t *testing.T) {
+		classification := ClassifyMX("acme.com", []string{"mx.unknown-host.net", "aspmx.l.google.com"})
+		assert.Equal(t, PROVIDER_GOOGLE, classification.Provider, "Expected a known backup host to classify the domain")
+	})
+
+	t.Run("TestSelfHostedAndOther", func(t *testing.T) {
+		assert.Equal(t, PROVIDER_SELF_HOSTED, ClassifyMX("Acme.co.uk", []string{"mail.acme.co.uk"}).Provider)
+		assert.Equal(t, PROVIDER_OTHER, ClassifyMX("acme.com", []string{"mx.hosting.net"}).Provider)
+		assert.Equal(t, PROVIDER_OTHER, ClassifyMX("acme.com", []string{"mail.notgoogle.com"}).Provider, "Expected suffixes to match whole labels")
+		assert.Equal(t, PROVIDER_NONE, ClassifyMX("acme.com", nil).Provider)
+	})
+}
+
+func TestClassifyProvider(t *testing.T) {
+	ctx := context.Background()
+	checker := NewDomainChecker(DomainCheckerOptions{Resolver: providerResolver()})
+
+	cases := map[string]string{
+		"gmail-corp.com": PROVIDER_GOOGLE,
+		"ms-corp.com":    PROVIDER_MICROSOFT,
+		"secure.com":     PROVIDER_PROOFPOINT,
+		"acme.co.uk":     PROVIDER_SELF_HOSTED,
+		"tiny.org":       PROVIDER_SELF_HOSTED,
+		"nullmx.com":     PROVIDER_NONE,
+		"nowhere.test":   PROVIDER_NONE,
+	}
+	for domain, provider := range cases {
+		classification, err := checker.ClassifyProvider(ctx, domain)
+		if assert.Nil(t, err, "Expected no error for %s", domain) {
+			assert.Equal(t, provider, classification.Provider, "Unexpected provider for %s", domain)
+		}
+	}
+}
+
+func TestValidateWithProviderDetection(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	defer SetProviderDetection(nil)
+
+	SetApiKey("test_api_key")
+	SetProviderDetection(NewDomainChecker(DomainCheckerOptions{Resolver: providerResolver()}))
+
+	httpmock.RegisterResponder("GET", `=~^(.*)`+ENDPOINT_VALIDATE+`(.*)\z`,
+		func(r *http.Request) (*http.Response, error) {
+			email := r.URL.Query().Get("email")
+			return httpmock.NewStringResponse(200, fmt.Sprintf(`{"email": %q, "status": "valid", "sub_status": "permitted"}`, email)), nil
+		},
+	)
+
+	t.Run("TestProviderAttached", func(t *testing.T) {
+		response, err := Validate("john@ms-corp.com")
+
+		assert.Nil(t, err, "Expected no error")
+		if assert.NotNil(t, response.Provider, "Expected the provider to be attached") {
+			assert.Equal(t, PROVIDER_MICROSOFT, response.Provider.Provider)
+			assert.Equal(t, "ms-corp.com", response.Provider.Domain)
+		}
+	})
+
+	t.Run("TestProviderWithoutRequest", func(t *testing.T) {
+		defer SetDNSPreCheck(nil)
+		defer SetDomainIntelligence(nil)
+		SetDNSPreCheck(NewDomainChecker(DomainCheckerOptions{Resolver: providerResolver()}))
+		intelligence := NewDomainIntelligence(DomainIntelligenceOptions{SkipCatchAll: true})
+		intelligence.Observe("anyone@gmail-corp.com", STATUS_CATCH_ALL, "")
+		SetDomainIntelligence(intelligence)
+		httpmock.ZeroCallCounters()
+
+		response, err := Validate("someone@nullmx.com")
+		assert.Nil(t, err, "Expected no error")
+		if assert.NotNil(t, response.Provider, "Expected the provider on a DNS pre-check answer") {
+			assert.Equal(t, PROVIDER_NONE, response.Provider.Provider)
+		}
+
+		response, err = Validate("someone@gmail-corp.com")
+		assert.Nil(t, err, "Expected no error")
+		if assert.NotNil(t, response.Provider, "Expected the provider on a domain intelligence answer") {
+			assert.Equal(t, PROVIDER_GOOGLE, response.Provider.Provider)
+		}
+		assert.Equal(t, 0, httpmock.GetTotalCallCount(), "Expected no API call")
+	})
+
+	t.Run("TestProviderInBatchResults", func(t *testing.T) {
+		httpmock.RegisterResponder("GET", `=~^(.*)`+ENDPOINT_BATCH_RESULT+`(.*)\z`,
+			httpmock.NewStringResponder(200, MOCK_BATCH_RESULTS_RESPONSE))
+
+		response, err := GetBatchResults(12345)
+
+		assert.Nil(t, err, "Expected no error")
+		for _, result := range response.Results.EmailBatch {
+			if assert.NotNil(t, result.Provider, "Expected a provider for %s", result.Address) {
+				assert.Equal(t, PROVIDER_GOOGLE, result.Provider.Provider)
+			}
+		}
+	})
+
+	t.Run("TestBatchDomainsLookedUpOnce", func(t *testing.T) {
+		resolver := &countingResolver{Resolver: providerResolver()}
+		SetProviderDetection(NewDomainChecker(DomainCheckerOptions{Resolver: resolver}))
+		httpmock.RegisterResponder("GET", `=~^(.*)`+ENDPOINT_BATCH_RESULT+`(.*)\z`,
+			httpmock.NewStringResponder(200, MOCK_BATCH_IN_PROGRESS_RESPONSE))
+
+		_, err := GetBatchResults(12345)
+		assert.Nil(t, err, "Expected no error")
+		assert.Equal(t, int32(0), resolver.lookups.Load(), "Expected no lookups while the batch is in progress")
+
+		httpmock.RegisterResponder("GET", `=~^(.*)`+ENDPOINT_BATCH_RESULT+`(.*)\z`,
+			httpmock.NewStringResponder(200, `{"status": "verified", "task_id": 12345,
+				"results": {"email_batch": [
+					{"address": "a@broken.test", "status": "unknown"},
+					{"address": "b@broken.test", "status": "unknown"},
+					{"address": "c@broken.test", "status": "unknown"}
+				]}}`))
+		resolver.Resolver.(*StaticResolver).Errors = map[string]error{"broken.test": &net.DNSError{Err: "i/o timeout", IsTimeout: true}}
+
+		response, err := GetBatchResults(12345)
+		assert.Nil(t, err, "Expected no error")
+		assert.Equal(t, int32(1), resolver.lookups.Load(), "Expected a failing domain to be looked up once per response")
+		for _, result := range response.Results.EmailBatch {
+			assert.Nil(t, result.Provider, "Expected no provider when the lookup fails")
+		}
+	})
+
+	t.Run("TestProviderInFindAndVerify", func(t *testing.T) {
+		httpmock.RegisterResponder("GET", `=~^(.*)`+ENDPOINT_EMAIL_FINDER+`(.*)\z`,
+			httpmock.NewStringResponder(200, `{"email": "jane@secure.com", "status": "found"}`))
+
+		response, err := FindAndVerify("Jane Doe", "secure.com")
+
+		assert.Nil(t, err, "Expected no error")
+		if assert.NotNil(t, response.Validation) && assert.NotNil(t, response.Validation.Provider) {
+			assert.True(t, response.Validation.Provider.IsGateway())
+		}
+	})
+}
+
+func TestReconciliationProviders(t *testing.T) {
+	checker := NewDomainChecker(DomainCheckerOptions{Resolver: providerResolver()})
+	results := &BatchResultResponse{}
+	results.Results.EmailBatch = []EmailBatchResult{
+		{Address: "a@gmail-corp.com", Status: STATUS_VALID},
+		{Address: "b@gmail-corp.com", Status: STATUS_VALID},
+		{Address: "c@ms-corp.com", Status: STATUS_INVALID},
+		{Address: "d@acme.co.uk", Status: STATUS_VALID},
+		{Address: "e@tiny.org", Status: STATUS_VALID},
+	}
+	submitted := []string{"a@gmail-corp.com", "b@gmail-corp.com", "c@ms-corp.com", "d@acme.co.uk", "e@tiny.org"}
+
+	report := ReconcileBatch(submitted, nil, results)
+	report.ClassifyProviders(context.Background(), checker, results)
+
+	if assert.Len(t, report.Providers, 3) {
+		assert.Equal(t, ProviderSummary{Provider: PROVIDER_GOOGLE, Kind: PROVIDER_KIND_MAILBOX, Domains: []string{"gmail-corp.com"}, Addresses: 2}, report.Providers[0])
+		assert.Equal(t, PROVIDER_SELF_HOSTED, report.Providers[1].Provider, "Expected ties to be ordered by name")
+		assert.ElementsMatch(t, []string{"acme.co.uk", "tiny.org"}, report.Providers[1].Domains)
+		assert.Equal(t, PROVIDER_MICROSOFT, report.Providers[2].Provider)
+	}
+	for _, result := range results.Results.EmailBatch {
+		assert.NotNil(t, result.Provider, "Expected %s to be classified", result.Address)
+	}
+	assert.Equal(t, PROVIDER_MICROSOFT, results.Results.EmailBatch[2].Provider.Provider)
+}
+
+func TestReconciliationProvidersWithoutChecker(t *testing.T) {
+	results := &BatchResultResponse{}
+	results.Results.EmailBatch = []EmailBatchResult{{Address: "a@gmail-corp.com", Status: STATUS_VALID}}
+	report := ReconcileBatch([]string{"a@gmail-corp.com"}, nil, results)
+
+	assert.NotPanics(t, func() { report.ClassifyProviders(context.Background(), nil, results) })
+	assert.Empty(t, report.Providers, "Expected nothing classified without a checker")
+
+	defer SetProviderDetection(nil)
+	SetProviderDetection(NewDomainChecker(DomainCheckerOptions{Resolver: providerResolver()}))
+	report.ClassifyProviders(context.Background(), nil, results)
+	assert.Len(t, report.Providers, 1, "Expected the provider detection checker to be used")
+}
+
+// countingResolver counts the MX lookups reaching a resolver
+type countingResolver struct {
+	Resolver
+	lookups atomic.Int32
+}
+
+func (r *countingResolver) LookupMX(ctx context.Context, name string) ([]*net.MX, error) {
+	r.lookups.Add(1)
+	return r.Resolver.LookupMX(ctx, name)
+}
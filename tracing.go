@@ -0,0 +1,84 @@
+package emailverifygo
+
+import (
+	"context"
+	"net/http"
+)
+
+// Attribute is a key/value pair recorded on a span
+type Attribute struct {
+	Key   string
+	Value any
+}
+
+// Attr creates an Attribute
+func Attr(key string, value any) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// Span is one traced operation. Adapters map it onto a real tracing SDK span.
+type Span interface {
+	SetAttributes(attrs ...Attribute)
+	RecordError(err error)
+	End()
+}
+
+// Tracer starts spans around every API operation and HTTP call. The package
+// depends only on this interface, so any tracing SDK can be plugged in with a
+// small adapter.
+type Tracer interface {
+	// Start creates a span as a child of any span in ctx and returns a context carrying it
+	Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span)
+}
+
+// TracePropagator can be implemented by a Tracer to add trace headers, such as
+// traceparent, to outgoing API requests
+type TracePropagator interface {
+	Inject(ctx context.Context, header http.Header)
+}
+
+// Span names used by the package
+const (
+	SPAN_VALIDATE          = "emailverify.Validate"
+	SPAN_VALIDATE_BATCH    = "emailverify.ValidateBatch"
+	SPAN_GET_BATCH_RESULTS = "emailverify.GetBatchResults"
+	SPAN_FIND_EMAIL        = "emailverify.FindEmail"
+	SPAN_ACCOUNT_BALANCE   = "emailverify.GetAccountBalance"
+	SPAN_HTTP              = "emailverify.http" // One per HTTP attempt
+)
+
+// tracer creates spans for every API operation. Nil disables tracing.
+var tracer Tracer
+
+// SetTracer sets the Tracer used to create spans. Pass nil to disable tracing, which is the default.
+func SetTracer(newTracer Tracer) {
+	tracer = newTracer
+}
+
+type noopSpan struct{}
+
+func (noopSpan) SetAttributes(attrs ...Attribute) {}
+func (noopSpan) RecordError(err error)            {}
+func (noopSpan) End()                             {}
+
+func startSpan(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span) {
+	if tracer == nil {
+		return ctx, noopSpan{}
+	}
+	return tracer.Start(ctx, name, attrs...)
+}
+
+// finishSpan records err, if any, and ends the span
+func finishSpan(span Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+	}
+	span.End()
+}
+
+// injectTrace adds trace headers to an outgoing request when the tracer supports it
+func injectTrace(req *http.Request) {
+	if propagator, ok := tracer.(TracePropagator); ok {
+		propagator.Inject(req.Context(), req.Header)
+	}
+}
@@ -0,0 +1,125 @@
+package emailverifygo
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+type parentKey struct{}
+
+type recordedSpan struct {
+	name   string
+	parent string
+	attrs  map[string]any
+	err    error
+	ended  bool
+}
+
+func (s *recordedSpan) SetAttributes(attrs ...Attribute) {
+	for _, attr := range attrs {
+		s.attrs[attr.Key] = attr.Value
+	}
+}
+func (s *recordedSpan) RecordError(err error) { s.err = err }
+func (s *recordedSpan) End()                  { s.ended = true }
+
+// recordingTracer keeps every span and propagates the parent span name as a header
+type recordingTracer struct {
+	mu    sync.Mutex
+	spans []*recordedSpan
+}
+
+func (r *recordingTracer) Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span) {
+	parent, _ := ctx.Value(parentKey{}).(string)
+	span := &recordedSpan{name: name, parent: parent, attrs: map[string]any{}}
+	span.SetAttributes(attrs...)
+
+	r.mu.Lock()
+	r.spans = append(r.spans, span)
+	r.mu.Unlock()
+	return context.WithValue(ctx, parentKey{}, name), span
+}
+
+func (r *recordingTracer) Inject(ctx context.Context, header http.Header) {
+	parent, _ := ctx.Value(parentKey{}).(string)
+	header.Set("X-Trace-Parent", parent)
+}
+
+func (r *recordingTracer) find(name string) *recordedSpan {
+	for _, span := range r.spans {
+		if span.name == name {
+			return span
+		}
+	}
+	return nil
+}
+
+func TestTracing(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	SetApiKey("test_api_key")
+
+	var injected string
+	httpmock.RegisterResponder("GET", `=~^(.*)`+ENDPOINT_VALIDATE+`(.*)\z`,
+		func(r *http.Request) (*http.Response, error) {
+			injected = r.Header.Get("X-Trace-Parent")
+			return httpmock.NewStringResponse(200, MOCK_VALID_RESPONSE), nil
+		},
+	)
+	httpmock.RegisterResponder("GET", `=~^(.*)`+ENDPOINT_BATCH_RESULT+`(.*)\z`,
+		httpmock.NewStringResponder(200, MOCK_BATCH_RESULTS_RESPONSE))
+	httpmock.RegisterResponder("GET", `=~^(.*)`+ENDPOINT_EMAIL_FINDER+`(.*)\z`,
+		httpmock.NewStringResponder(401, MOCK_ERROR_RESPONSE))
+
+	t.Run("TestValidateSpans", func(t *testing.T) {
+		recorder := &recordingTracer{}
+		SetTracer(recorder)
+		defer SetTracer(nil)
+
+		ctx := context.WithValue(context.Background(), parentKey{}, "signup")
+		_, err := ValidateContext(ctx, "valid@example.com")
+		assert.Nil(t, err, "Expected no error")
+
+		operation := recorder.find(SPAN_VALIDATE)
+		assert.Equal(t, "signup", operation.parent, "Expected the caller's span to be the parent")
+		assert.Equal(t, STATUS_VALID, operation.attrs["status"], "Expected the status attribute")
+		assert.True(t, operation.ended, "Expected the operation span to end")
+
+		attempt := recorder.find(SPAN_HTTP)
+		assert.Equal(t, SPAN_VALIDATE, attempt.parent, "Expected the HTTP span inside the operation span")
+		assert.Equal(t, 200, attempt.attrs["http.status_code"], "Expected the HTTP status attribute")
+		assert.Equal(t, 1, attempt.attrs["attempt"], "Expected the attempt number attribute")
+		assert.Equal(t, SPAN_HTTP, injected, "Expected trace headers on the outgoing request")
+	})
+
+	t.Run("TestBatchResultSpan", func(t *testing.T) {
+		recorder := &recordingTracer{}
+		SetTracer(recorder)
+		defer SetTracer(nil)
+
+		GetBatchResults(12345)
+
+		span := recorder.find(SPAN_GET_BATCH_RESULTS)
+		assert.Equal(t, 12345, span.attrs["task_id"], "Expected the task ID attribute")
+		assert.Equal(t, 1, span.attrs["results.status.valid"], "Expected status counts")
+		assert.Equal(t, 2, span.attrs["results.status.invalid"], "Expected status counts")
+		assert.Equal(t, 1, span.attrs["results.sub_status.no_dns_entries"], "Expected sub-status counts")
+	})
+
+	t.Run("TestErrorRecorded", func(t *testing.T) {
+		recorder := &recordingTracer{}
+		SetTracer(recorder)
+		defer SetTracer(nil)
+
+		FindEmail("John Doe", "example.com")
+
+		assert.NotNil(t, recorder.find(SPAN_FIND_EMAIL).err, "Expected the error on the operation span")
+		assert.NotNil(t, recorder.find(SPAN_HTTP).err, "Expected the error on the HTTP span")
+	})
+}
@@ -1,14 +1,19 @@
 package emailverifygo
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/mail"
 	"net/url"
 	"os"
+	"regexp"
 	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -22,6 +27,12 @@ const (
 	ENDPOINT_BATCH_RESULT          = "/api/v1/get-result-bulk-verification-task"
 )
 
+// Credits consumed by a successful call
+const (
+	CREDITS_PER_VALIDATION = 1 // One single email validation
+	CREDITS_PER_FINDER     = 1 // One email finder lookup
+)
+
 // Email validation status constants
 const (
 	STATUS_VALID       = "valid"       // The email is valid and deliverable
@@ -52,6 +63,39 @@ type APIResponse interface{}
 // ErrMissingAPIKey is returned when the API key is not set
 var ErrMissingAPIKey = errors.New("API key not set. Use SetApiKey() or LoadEnvFromFile() to set it")
 
+// REDACTED replaces the API key wherever the package reports a URL, body or error
+const REDACTED = "[REDACTED]"
+
+// keyParamPattern matches the value of a `key` query parameter
+var keyParamPattern = regexp.MustCompile(`((?:^|[?&])key=)[^&\s"']*`)
+
+// NormalizeEmail trims surrounding whitespace and lower-cases an address,
+// so the same mailbox written differently compares equal
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// CheckEmailSyntax performs a local syntax check of an address without calling the API.
+// It returns nil when the address looks deliverable, or an error describing the problem.
+func CheckEmailSyntax(email string) error {
+	email = strings.TrimSpace(email)
+	if email == "" {
+		return fmt.Errorf("email cannot be empty")
+	}
+
+	parsed, err := mail.ParseAddress(email)
+	if err != nil || parsed.Address != email || parsed.Name != "" {
+		return fmt.Errorf("invalid email syntax: %q", email)
+	}
+
+	at := strings.LastIndex(email, "@")
+	domain := email[at+1:]
+	if !strings.Contains(domain, ".") || strings.HasPrefix(domain, ".") || strings.HasSuffix(domain, ".") {
+		return fmt.Errorf("invalid email domain: %q", domain)
+	}
+	return nil
+}
+
 // Getenv gets an environment variable or returns a default value if it's not set
 func Getenv(key, fallback string) string {
 	value := os.Getenv(key)
@@ -68,6 +112,10 @@ var (
 
 	// API_KEY the API key used in order to make the requests
 	API_KEY string = os.Getenv("EMAIL_VERIFY_API_KEY")
+
+	// API_KEY_HEADER, when set, is the header the API key is sent in
+	// instead of the `key` query parameter and batch body field
+	API_KEY_HEADER string = os.Getenv("EMAIL_VERIFY_API_KEY_HEADER")
 )
 
 // GetBaseURI returns the current base URI for the API
@@ -80,6 +128,66 @@ func SetApiKey(newApiKey string) {
 	API_KEY = newApiKey
 }
 
+// SetApiKeyHeader sends the API key in the named header instead of the URL and
+// request body, for endpoints that support it. Pass an empty name to go back to
+// the `key` parameter.
+func SetApiKeyHeader(name string) {
+	API_KEY_HEADER = name
+}
+
+// RedactAPIKey removes the API key, and the value of any `key` query parameter, from s
+func RedactAPIKey(s string) string {
+	if API_KEY != "" {
+		s = strings.ReplaceAll(s, API_KEY, REDACTED)
+		if escaped := url.QueryEscape(API_KEY); escaped != API_KEY {
+			s = strings.ReplaceAll(s, escaped, REDACTED)
+		}
+	}
+	return keyParamPattern.ReplaceAllString(s, "${1}"+REDACTED)
+}
+
+// redactedError hides the API key from an error message while keeping the
+// original error available to errors.Is and errors.As
+type redactedError struct {
+	err error
+}
+
+func (e *redactedError) Error() string {
+	return RedactAPIKey(e.err.Error())
+}
+
+// Unwrap returns the wrapped error when its message is clean. A wrapper whose
+// message was formatted before the key beneath it was scrubbed, as fmt.Errorf
+// does, is skipped, and what it wraps is redacted in turn.
+func (e *redactedError) Unwrap() error {
+	if message := e.err.Error(); RedactAPIKey(message) == message {
+		return e.err
+	}
+	if next := errors.Unwrap(e.err); next != nil {
+		return &redactedError{err: next}
+	}
+	return nil
+}
+
+// redactError wraps err so its message never contains the API key. The URL of a
+// wrapped *url.Error and the message of an *APIError are scrubbed too, since
+// callers can unwrap them.
+func redactError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		urlErr.URL = RedactAPIKey(urlErr.URL)
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		apiErr.Message = RedactAPIKey(apiErr.Message)
+	}
+	return &redactedError{err: err}
+}
+
 // SetURI updates the base URI for the API
 // Useful for testing against staging environments
 func SetURI(newURI string) {
@@ -109,8 +217,10 @@ func PrepareURL(endpoint string, params url.Values) (string, error) {
 		return "", ErrMissingAPIKey
 	}
 
-	// Set API KEY
-	params.Set("key", API_KEY)
+	// Set API KEY, unless it travels in a header
+	if API_KEY_HEADER == "" {
+		params.Set("key", API_KEY)
+	}
 
 	// Create and return the final URL
 	finalURL, err := url.JoinPath(URI, endpoint)
@@ -120,6 +230,44 @@ func PrepareURL(endpoint string, params url.Values) (string, error) {
 	return fmt.Sprintf("%s?%s", finalURL, params.Encode()), nil
 }
 
+// Error type labels reported to Metrics
+const (
+	ERROR_TYPE_TRANSPORT    = "transport"    // The request never got a response
+	ERROR_TYPE_DECODE       = "decode"       // The response was not the expected JSON
+	ERROR_TYPE_UNAUTHORIZED = "unauthorized" // The API rejected the key (401, 403)
+	ERROR_TYPE_RATE_LIMITED = "rate_limited" // The API throttled the request (429)
+	ERROR_TYPE_CLIENT       = "client_error" // Any other 4xx response
+	ERROR_TYPE_SERVER       = "server_error" // A 5xx response
+)
+
+// APIError is returned when the API answers with a non-200 status code
+type APIError struct {
+	StatusCode int    // The HTTP status code of the response
+	Message    string // The error details from the response
+	raw        bool   // The response was not JSON, Message is the raw body
+}
+
+func (e *APIError) Error() string {
+	if e.raw {
+		return fmt.Sprintf("API error (status %d): %s", e.StatusCode, e.Message)
+	}
+	return fmt.Sprintf("API error %d: %s", e.StatusCode, e.Message)
+}
+
+// Type classifies the error by status code, using the ERROR_TYPE_* labels
+func (e *APIError) Type() string {
+	switch {
+	case e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden:
+		return ERROR_TYPE_UNAUTHORIZED
+	case e.StatusCode == http.StatusTooManyRequests:
+		return ERROR_TYPE_RATE_LIMITED
+	case e.StatusCode >= 500:
+		return ERROR_TYPE_SERVER
+	default:
+		return ERROR_TYPE_CLIENT
+	}
+}
+
 // ErrorFromResponse parses an error response from the API
 // This handles the inconsistent error message formats returned by the API
 func ErrorFromResponse(response *http.Response) error {
@@ -135,7 +283,7 @@ func ErrorFromResponse(response *http.Response) error {
 
 	if err != nil {
 		// unexpected non-json payload
-		return fmt.Errorf("API error (status %d): %s", response.StatusCode, string(responseBody))
+		return &APIError{StatusCode: response.StatusCode, Message: string(responseBody), raw: true}
 	}
 
 	// return all possible details about the error
@@ -143,65 +291,106 @@ func ErrorFromResponse(response *http.Response) error {
 	for _, value := range errorResponse {
 		errorStrings = append(errorStrings, value)
 	}
-	return fmt.Errorf("API error %d: %s", response.StatusCode, strings.Join(errorStrings, ", "))
+	return &APIError{StatusCode: response.StatusCode, Message: strings.Join(errorStrings, ", ")}
 }
 
 // DoGetRequest performs a GET request to the API
 func DoGetRequest(url string, object APIResponse) error {
-	// Create a new request
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
-	}
-	
-	// Do the request using the current HTTP client
-	response, err := httpClient.Do(req)
-
-	if err != nil {
-		return fmt.Errorf("HTTP request failed: %w", err)
-	}
+	return doGetRequest(context.Background(), url, object)
+}
 
-	// Close the request
-	defer response.Body.Close()
-	if response.StatusCode != 200 {
-		return ErrorFromResponse(response)
-	}
+// DoPostRequest performs a POST request to the API
+func DoPostRequest(url string, payload io.Reader, object APIResponse) error {
+	return doPostRequest(context.Background(), url, payload, object)
+}
 
-	// Decode JSON Request
-	err = json.NewDecoder(response.Body).Decode(&object)
+func doGetRequest(ctx context.Context, url string, object APIResponse) error {
+	// Create a new request
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
-		return fmt.Errorf("failed to decode JSON response: %w", err)
+		return redactError(fmt.Errorf("failed to create request: %w", err))
 	}
-	return nil
+	return doRequest(req, object)
 }
 
-// DoPostRequest performs a POST request to the API
-func DoPostRequest(url string, payload io.Reader, object APIResponse) error {
+func doPostRequest(ctx context.Context, url string, payload io.Reader, object APIResponse) error {
 	// Create a new request
-	req, err := http.NewRequest("POST", url, payload)
+	req, err := http.NewRequestWithContext(ctx, "POST", url, payload)
 	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+		return redactError(fmt.Errorf("failed to create request: %w", err))
 	}
 	
 	// Set content type
 	req.Header.Set("Content-Type", "application/json")
-	
+	return doRequest(req, object)
+}
+
+// doRequest sends a prepared request and decodes the JSON response into object.
+// Every error it returns has the API key redacted.
+func doRequest(req *http.Request, object APIResponse) (err error) {
+	if API_KEY_HEADER != "" && API_KEY != "" {
+		req.Header.Set(API_KEY_HEADER, API_KEY)
+	}
+
+	ctx, span := startSpan(req.Context(), SPAN_HTTP,
+		Attr("http.method", req.Method),
+		Attr("endpoint", req.URL.Path),
+		Attr("attempt", requestAttempt(req.Context())),
+	)
+	req = req.WithContext(ctx)
+	injectTrace(req)
+
+	dumpRequest(req)
+	start := time.Now()
+	statusCode := 0
+	errorType := ""
+	defer func() {
+		latency := time.Since(start)
+		logRequest(req, statusCode, latency, err)
+		if metrics != nil {
+			metrics.ObserveRequest(req.URL.Path, statusCode, latency, errorType)
+		}
+		span.SetAttributes(Attr("http.status_code", statusCode))
+		finishSpan(span, err)
+	}()
+
 	// Do the request using the current HTTP client
 	response, err := httpClient.Do(req)
 	if err != nil {
-		return fmt.Errorf("HTTP request failed: %w", err)
+		errorType = ERROR_TYPE_TRANSPORT
+		return redactError(fmt.Errorf("HTTP request failed: %w", err))
 	}
 
 	// Close the request
 	defer response.Body.Close()
+	statusCode = response.StatusCode
+
+	// Keep a copy of the body for the debug dump
+	if logger != nil && debugDumps {
+		body, err := io.ReadAll(response.Body)
+		if err != nil {
+			errorType = ERROR_TYPE_TRANSPORT
+			return redactError(fmt.Errorf("failed to read response: %w", err))
+		}
+		dumpResponse(req, statusCode, body)
+		response.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
 	if response.StatusCode != 200 {
-		return ErrorFromResponse(response)
+		err = ErrorFromResponse(response)
+		errorType = ERROR_TYPE_TRANSPORT
+		var apiErr *APIError
+		if errors.As(err, &apiErr) {
+			errorType = apiErr.Type()
+		}
+		return redactError(err)
 	}
 
 	// Decode JSON Request
 	err = json.NewDecoder(response.Body).Decode(&object)
 	if err != nil {
-		return fmt.Errorf("failed to decode JSON response: %w", err)
+		errorType = ERROR_TYPE_DECODE
+		return redactError(fmt.Errorf("failed to decode JSON response: %w", err))
 	}
 	return nil
 }
@@ -0,0 +1,88 @@
+package emailverifygo
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApiKeyHeader(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	SetApiKey("test_api_key")
+	SetApiKeyHeader("X-Api-Key")
+	defer SetApiKeyHeader("")
+
+	httpmock.RegisterResponder("GET", `=~^(.*)`+ENDPOINT_VALIDATE+`(.*)\z`,
+		func(r *http.Request) (*http.Response, error) {
+			if r.URL.Query().Has("key") || r.Header.Get("X-Api-Key") != "test_api_key" {
+				return httpmock.NewStringResponse(400, `{"error": "Expected the key in the header only"}`), nil
+			}
+			return httpmock.NewStringResponse(200, MOCK_VALID_RESPONSE), nil
+		},
+	)
+	httpmock.RegisterResponder("POST", `=~^(.*)`+ENDPOINT_VALIDATE_BATCH+`(.*)\z`,
+		func(r *http.Request) (*http.Response, error) {
+			var body map[string]any
+			json.NewDecoder(r.Body).Decode(&body)
+			if _, ok := body["key"]; ok || r.Header.Get("X-Api-Key") != "test_api_key" {
+				return httpmock.NewStringResponse(400, `{"error": "Expected the key in the header only"}`), nil
+			}
+			return httpmock.NewStringResponse(200, MOCK_BATCH_RESPONSE), nil
+		},
+	)
+
+	_, err := Validate("valid@example.com")
+	assert.Nil(t, err, "Expected the key to be sent in the header")
+
+	_, err = ValidateBatch("Test Batch", []string{"valid@example.com"})
+	assert.Nil(t, err, "Expected the batch key to be sent in the header")
+}
+
+func TestRedaction(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	SetApiKey("secret/key+1")
+	defer SetApiKey("test_api_key")
+
+	t.Run("TestTransportErrorRedacted", func(t *testing.T) {
+		httpmock.RegisterResponder("GET", `=~^(.*)`+ENDPOINT_VALIDATE+`(.*)\z`,
+			httpmock.NewErrorResponder(errors.New("connection refused")))
+
+		_, err := Validate("valid@example.com")
+		assert.NotNil(t, err, "Expected a transport error")
+		assert.NotContains(t, err.Error(), "secret", "Expected the key to be redacted from the message")
+		assert.Contains(t, err.Error(), "key="+REDACTED, "Expected a redaction marker")
+
+		var urlErr *url.Error
+		assert.True(t, errors.As(err, &urlErr), "Expected the url.Error to stay reachable")
+		assert.NotContains(t, urlErr.URL, "secret", "Expected the unwrapped URL to be redacted")
+
+		for link := err; link != nil; link = errors.Unwrap(link) {
+			assert.NotContains(t, link.Error(), "secret", "Expected every error in the chain to be redacted")
+			assert.NotContains(t, link.Error(), url.QueryEscape("secret/key+1"), "Expected every error in the chain to be redacted")
+		}
+	})
+
+	t.Run("TestAPIErrorRedacted", func(t *testing.T) {
+		httpmock.RegisterResponder("GET", `=~^(.*)`+ENDPOINT_ACCOUNT_BALANCE+`(.*)\z`,
+			httpmock.NewStringResponder(401, `{"error": "Invalid key secret/key+1"}`))
+
+		_, err := GetAccountBalance()
+		assert.NotNil(t, err, "Expected an API error")
+		assert.Equal(t, "API error 401: Invalid key "+REDACTED, err.Error(), "Expected the echoed key to be redacted")
+	})
+
+	t.Run("TestRedactAPIKey", func(t *testing.T) {
+		assert.Equal(t, "https://proxy/api?email=a%40b.com&key="+REDACTED,
+			RedactAPIKey("https://proxy/api?email=a%40b.com&key=other-token"), "Expected any key parameter to be redacted")
+		assert.Equal(t, "no secrets here", RedactAPIKey("no secrets here"), "Expected other text untouched")
+	})
+}